@@ -0,0 +1,141 @@
+package terminalformat
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Bar is a horizontal bar, which consists of a base segment, and optionally
+// of a segment that exceeds a certain value (e.g. overtime) or of a segment
+// that falls short of it (e.g. undertime).
+type Bar struct {
+	Base    int
+	Excess  int
+	Deficit int
+}
+
+func (b Bar) length() int {
+	return positive(b.Base) + positive(b.Excess) + positive(b.Deficit)
+}
+
+// BarChart appends proportional bars to the rows of a table.
+type BarChart struct {
+	bars     []*Bar
+	isStyled bool
+}
+
+const minBarWidth = 10
+
+var partialBlocks = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+type barSegment struct {
+	value      int
+	style      Style
+	char       string
+	plainChar  string
+	hasPartial bool
+}
+
+// NewBarChart creates a chart that is either drawn with (coloured) block
+// characters, or, if not styled, with plain ASCII characters.
+func NewBarChart(isStyled bool) *BarChart {
+	return &BarChart{isStyled: isStyled}
+}
+
+// Bar adds a bar for the next row of the table.
+func (c *BarChart) Bar(b Bar) *BarChart {
+	c.bars = append(c.bars, &b)
+	return c
+}
+
+// Skip adds rows that don’t have a bar.
+func (c *BarChart) Skip(numberOfRows int) *BarChart {
+	for i := 0; i < numberOfRows; i++ {
+		c.bars = append(c.bars, nil)
+	}
+	return c
+}
+
+// Collect renders the table along with the bars. The bars are scaled so that
+// each row fits into `maxWidth`, if possible.
+func (c *BarChart) Collect(table *Table, maxWidth int, fn func(string)) {
+	output := ""
+	table.Collect(func(s string) { output += s })
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	lineWidth := 0
+	for _, l := range lines {
+		if w := utf8.RuneCountInString(StripAllAnsiSequences(l)); w > lineWidth {
+			lineWidth = w
+		}
+	}
+	barWidth := maxWidth - lineWidth - len(table.columnSeparator)
+	if barWidth < minBarWidth {
+		barWidth = minBarWidth
+	}
+	maxLength := 0
+	for _, b := range c.bars {
+		if b != nil && b.length() > maxLength {
+			maxLength = b.length()
+		}
+	}
+	for i, l := range lines {
+		fn(l)
+		if i < len(c.bars) && c.bars[i] != nil && maxLength > 0 {
+			fn(table.columnSeparator + c.render(*c.bars[i], maxLength, barWidth))
+		}
+		fn("\n")
+	}
+}
+
+func (c *BarChart) render(b Bar, maxLength int, width int) string {
+	segments := []barSegment{
+		{positive(b.Base), Style{Color: "249"}, "█", "#", true},
+		{positive(b.Excess), Style{Color: "120"}, "█", "+", true},
+		{positive(b.Deficit), Style{Color: "167"}, "░", "-", false},
+	}
+	result := ""
+	position := 0
+	drawnCells := 0
+	for i, s := range segments {
+		if s.value == 0 {
+			continue
+		}
+		position += s.value
+		eighths := position * width * 8 / maxLength
+		isLast := true
+		for _, next := range segments[i+1:] {
+			if next.value > 0 {
+				isLast = false
+			}
+		}
+		if !c.isStyled {
+			cells := (eighths+4)/8 - drawnCells
+			result += strings.Repeat(s.plainChar, cells)
+			drawnCells += cells
+			continue
+		}
+		text := ""
+		if isLast && s.hasPartial {
+			cells := eighths/8 - drawnCells
+			if cells >= 0 {
+				text = strings.Repeat(s.char, cells) + partialBlocks[eighths%8]
+				drawnCells += cells
+			}
+		} else {
+			cells := (eighths+4)/8 - drawnCells
+			text = strings.Repeat(s.char, cells)
+			drawnCells += cells
+		}
+		if text != "" {
+			result += s.style.Format(text)
+		}
+	}
+	return result
+}
+
+func positive(value int) int {
+	if value < 0 {
+		return 0
+	}
+	return value
+}
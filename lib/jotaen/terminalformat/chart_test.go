@@ -0,0 +1,49 @@
+package terminalformat
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPrintBarChartInPlainCharacters(t *testing.T) {
+	result := ""
+	table := NewTable(2, " ")
+	table.CellL("Name").CellR("Value").CellL("A").CellR("8").CellL("B").CellR("4").CellL("C").CellR("0")
+	NewBarChart(false).
+		Skip(1).
+		Bar(Bar{Base: 8}).
+		Bar(Bar{Base: 2, Excess: 2}).
+		Bar(Bar{Base: 0, Deficit: 4}).
+		Collect(table, 23, func(s string) { result += s })
+	assert.Equal(t, `Name Value
+A        8 ############
+B        4 ###+++
+C        0 ------
+`, result)
+}
+
+func TestPrintBarChartWithBlockCharacters(t *testing.T) {
+	result := ""
+	table := NewTable(2, " ")
+	table.CellL("A").CellR("10").CellL("B").CellR("1")
+	NewBarChart(true).
+		Bar(Bar{Base: 10}).
+		Bar(Bar{Base: 1}).
+		Collect(table, 14, func(s string) { result += StripAllAnsiSequences(s) })
+	assert.Equal(t, `A 10 ██████████
+B  1 █
+`, result)
+}
+
+func TestBarChartHasMinimumWidth(t *testing.T) {
+	result := ""
+	table := NewTable(2, " ")
+	table.CellL("A").CellR("3").CellL("B").CellR("1")
+	NewBarChart(true).
+		Bar(Bar{Base: 16}).
+		Bar(Bar{Base: 3}).
+		Collect(table, 0, func(s string) { result += StripAllAnsiSequences(s) })
+	assert.Equal(t, `A 3 ██████████
+B 1 █▉
+`, result)
+}
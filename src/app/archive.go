@@ -0,0 +1,65 @@
+package app
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ArchiveFile returns the file that holds the archived records of the given
+// year, e.g. `/files/work.2021.klg` for `/files/work.klg`.
+func ArchiveFile(original File, year int) File {
+	return NewFileOrPanic(filepath.Join(
+		original.Location(),
+		archiveStem(original)+"."+strconv.Itoa(year)+".klg",
+	))
+}
+
+// ArchiveBookmarkName returns the name under which an archive is bookmarked,
+// e.g. `work-2021` for `work`.
+func ArchiveBookmarkName(name string, year int) string {
+	return NewName(name).Value() + "-" + strconv.Itoa(year)
+}
+
+func archiveStem(f File) string {
+	return strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+}
+
+// findArchives looks up the bookmarked archives of the given files, as far as
+// the years of the archives are included.
+func findArchives(
+	bc BookmarksCollection,
+	files []*fileWithContent,
+	isYearIncluded func(int) bool,
+) []FileOrBookmarkName {
+	included := make(map[string]bool)
+	for _, f := range files {
+		if f.File != nil {
+			included[f.Path()] = true
+		}
+	}
+	var archives []FileOrBookmarkName
+	for _, f := range files {
+		if f.File == nil {
+			continue
+		}
+		pattern := regexp.MustCompile(
+			"^" + regexp.QuoteMeta(filepath.Join(f.Location(), archiveStem(f))) + `\.(\d{4})\.klg$`,
+		)
+		for _, b := range bc.All() {
+			path := b.Target().Path()
+			match := pattern.FindStringSubmatch(path)
+			if match == nil || included[path] {
+				continue
+			}
+			year, _ := strconv.Atoi(match[1])
+			if !isYearIncluded(year) {
+				continue
+			}
+			included[path] = true
+			archives = append(archives, FileOrBookmarkName(path))
+		}
+	}
+	return archives
+}
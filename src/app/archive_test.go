@@ -0,0 +1,30 @@
+package app
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestArchiveFileIsNextToOriginal(t *testing.T) {
+	archive := ArchiveFile(NewFileOrPanic("/files/work.klg"), 2021)
+	assert.Equal(t, "/files/work.2021.klg", archive.Path())
+	assert.Equal(t, "work-2021", ArchiveBookmarkName("@work", 2021))
+}
+
+func TestFindsBookmarkedArchivesOfIncludedYears(t *testing.T) {
+	bc := NewEmptyBookmarksCollection()
+	bc.Set(NewBookmark("work", NewFileOrPanic("/files/work.klg")))
+	bc.Set(NewBookmark("work-2019", NewFileOrPanic("/files/work.2019.klg")))
+	bc.Set(NewBookmark("work-2020", NewFileOrPanic("/files/work.2020.klg")))
+	bc.Set(NewBookmark("work-2021", NewFileOrPanic("/files/work.2021.klg")))
+	bc.Set(NewBookmark("other-2020", NewFileOrPanic("/files/other.2020.klg")))
+	bc.Set(NewBookmark("elsewhere", NewFileOrPanic("/elsewhere/work.2020.klg")))
+
+	archives := findArchives(bc, []*fileWithContent{
+		{NewFileOrPanic("/files/work.klg"), ""},
+		{NewFileOrPanic("/files/work.2021.klg"), ""},
+		{nil, ""},
+	}, func(year int) bool { return year >= 2020 })
+
+	assert.Equal(t, []FileOrBookmarkName{"/files/work.2020.klg"}, archives)
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"encoding/json"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/service"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// NewHolidaysFromCalendar parses the contents of a holiday calendar file.
+// That can either be an iCalendar file (`.ics`), or a JSON file that
+// contains a list of dates, e.g. `["2020-12-25"]`, or a list of objects,
+// e.g. `[{"date": "2020-12-25", "name": "Christmas"}]`.
+func NewHolidaysFromCalendar(path string, contents string) ([]service.Holiday, Error) {
+	if strings.ToLower(filepath.Ext(path)) == ".ics" {
+		return parseIcsCalendar(path, contents)
+	}
+	return parseJsonCalendar(path, contents)
+}
+
+func newCalendarError(path string, err error) Error {
+	return NewErrorWithCode(
+		CONFIG_ERROR,
+		"Invalid holiday calendar",
+		"Location: "+path,
+		err,
+	)
+}
+
+func parseJsonCalendar(path string, contents string) ([]service.Holiday, Error) {
+	var raw []json.RawMessage
+	err := json.Unmarshal([]byte(contents), &raw)
+	if err != nil {
+		return nil, newCalendarError(path, err)
+	}
+	var holidays []service.Holiday
+	for _, item := range raw {
+		var entry struct {
+			Date string `json:"date"`
+			Name string `json:"name"`
+		}
+		if sErr := json.Unmarshal(item, &entry.Date); sErr != nil {
+			if oErr := json.Unmarshal(item, &entry); oErr != nil {
+				return nil, newCalendarError(path, oErr)
+			}
+		}
+		d, dErr := NewDateFromString(entry.Date)
+		if dErr != nil {
+			return nil, newCalendarError(path, dErr)
+		}
+		holidays = append(holidays, service.Holiday{Date: d, Name: entry.Name})
+	}
+	return holidays, nil
+}
+
+func parseIcsCalendar(path string, contents string) ([]service.Holiday, Error) {
+	var holidays []service.Holiday
+	var start, end Date
+	var name string
+	isInEvent := false
+	for _, line := range unfoldIcsLines(contents) {
+		property, value := splitIcsLine(line)
+		switch {
+		case property == "BEGIN" && value == "VEVENT":
+			isInEvent = true
+			start, end, name = nil, nil, ""
+		case property == "END" && value == "VEVENT":
+			isInEvent = false
+			if start == nil {
+				return nil, newCalendarError(path, nil)
+			}
+			holidays = append(holidays, service.Holiday{Date: start, Name: name})
+			// The end date of an all-day event is exclusive.
+			for d := start.PlusDays(1); end != nil && !d.IsAfterOrEqual(end); d = d.PlusDays(1) {
+				holidays = append(holidays, service.Holiday{Date: d, Name: name})
+			}
+		case !isInEvent:
+			continue
+		case property == "DTSTART" || property == "DTEND":
+			d, dErr := parseIcsDate(value)
+			if dErr != nil {
+				return nil, newCalendarError(path, dErr)
+			}
+			if property == "DTSTART" {
+				start = d
+			} else if len(value) == 8 {
+				end = d
+			}
+		case property == "SUMMARY":
+			name = strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\\`, `\`).Replace(value)
+		}
+	}
+	return holidays, nil
+}
+
+// unfoldIcsLines joins lines that are continued on the next line (which is
+// indicated by leading whitespace).
+func unfoldIcsLines(contents string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(contents, "\r\n", "\n"), "\n") {
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitIcsLine returns the name and the value of a property, disregarding
+// any parameters, e.g. `DTSTART;VALUE=DATE:20201225`.
+func splitIcsLine(line string) (string, string) {
+	parts := strings.SplitN(line, ":", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	name := strings.SplitN(parts[0], ";", 2)[0]
+	return strings.ToUpper(strings.TrimSpace(name)), strings.TrimSpace(parts[1])
+}
+
+func parseIcsDate(value string) (Date, error) {
+	invalidDateErr := NewError("Invalid date", value, nil)
+	if len(value) < 8 {
+		return nil, invalidDateErr
+	}
+	year, yErr := strconv.Atoi(value[0:4])
+	month, mErr := strconv.Atoi(value[4:6])
+	day, dErr := strconv.Atoi(value[6:8])
+	if yErr != nil || mErr != nil || dErr != nil {
+		return nil, invalidDateErr
+	}
+	return NewDate(year, month, day)
+}
+
+// calendarsFor returns the paths of all calendars that apply to the given
+// inputs. Without inputs, the default bookmark is assumed.
+func (c Config) calendarsFor(bc BookmarksCollection, fileArgs []FileOrBookmarkName) []string {
+	calendars := append([]string(nil), c.Calendars...)
+	if len(c.BookmarkCalendars) == 0 {
+		return calendars
+	}
+	var names []Name
+	if len(fileArgs) == 0 {
+		names = append(names, Name(BOOKMARK_DEFAULT_NAME))
+	}
+	for _, arg := range fileArgs {
+		if IsValidBookmarkName(string(arg)) {
+			names = append(names, NewName(string(arg)))
+			continue
+		}
+		file, err := NewFile(string(arg))
+		if err != nil {
+			continue
+		}
+		for _, b := range bc.All() {
+			if b.Target().Path() == file.Path() {
+				names = append(names, b.Name())
+			}
+		}
+	}
+	for _, n := range names {
+		calendars = append(calendars, c.BookmarkCalendars[n]...)
+	}
+	return calendars
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParsesJsonCalendar(t *testing.T) {
+	holidays, err := NewHolidaysFromCalendar("/holidays.json", `[
+  "2020-12-24",
+  {"date": "2020-12-25", "name": "Christmas"}
+]`)
+	require.Nil(t, err)
+	assert.Equal(t, []service.Holiday{
+		{Date: Ɀ_Date_(2020, 12, 24)},
+		{Date: Ɀ_Date_(2020, 12, 25), Name: "Christmas"},
+	}, holidays)
+}
+
+func TestParsesIcsCalendar(t *testing.T) {
+	holidays, err := NewHolidaysFromCalendar("/holidays.ics", "BEGIN:VCALENDAR\r\n"+
+		"VERSION:2.0\r\n"+
+		"BEGIN:VEVENT\r\n"+
+		"DTSTART;VALUE=DATE:20201225\r\n"+
+		"DTEND;VALUE=DATE:20201227\r\n"+
+		"SUMMARY:Christmas\\, \r\n"+
+		" Boxing Day\r\n"+
+		"END:VEVENT\r\n"+
+		"BEGIN:VEVENT\r\n"+
+		"DTSTART:20210101T000000Z\r\n"+
+		"SUMMARY:New Year\r\n"+
+		"END:VEVENT\r\n"+
+		"END:VCALENDAR\r\n")
+	require.Nil(t, err)
+	assert.Equal(t, []service.Holiday{
+		{Date: Ɀ_Date_(2020, 12, 25), Name: "Christmas, Boxing Day"},
+		{Date: Ɀ_Date_(2020, 12, 26), Name: "Christmas, Boxing Day"},
+		{Date: Ɀ_Date_(2021, 1, 1), Name: "New Year"},
+	}, holidays)
+}
+
+func TestRejectsMalformedCalendars(t *testing.T) {
+	for _, c := range []struct {
+		path     string
+		contents string
+	}{
+		{"/holidays.json", `{"date": "2020-12-25"}`},
+		{"/holidays.json", `["2020-13-25"]`},
+		{"/holidays.ics", "BEGIN:VEVENT\nDTSTART:2020\nEND:VEVENT"},
+		{"/holidays.ics", "BEGIN:VEVENT\nSUMMARY:Christmas\nEND:VEVENT"},
+	} {
+		_, err := NewHolidaysFromCalendar(c.path, c.contents)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestDeterminesCalendarsForInputs(t *testing.T) {
+	bc := NewEmptyBookmarksCollection()
+	bc.Set(NewDefaultBookmark(NewFileOrPanic("/home/work.klg")))
+	bc.Set(NewBookmark("side", NewFileOrPanic("/home/side.klg")))
+	config := Config{
+		Calendars: []string{"/global.ics"},
+		BookmarkCalendars: map[Name][]string{
+			"default": {"/work.json"},
+			"side":    {"/side.json"},
+		},
+	}
+	assert.Equal(t, []string{"/global.ics", "/work.json"}, config.calendarsFor(bc, nil))
+	assert.Equal(t, []string{"/global.ics", "/side.json"}, config.calendarsFor(bc, []FileOrBookmarkName{"@side"}))
+	assert.Equal(t, []string{"/global.ics", "/side.json"}, config.calendarsFor(bc, []FileOrBookmarkName{"/home/side.klg"}))
+	assert.Equal(t, []string{"/global.ics"}, config.calendarsFor(bc, []FileOrBookmarkName{"/home/other.klg"}))
+}
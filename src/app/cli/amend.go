@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"errors"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/parser"
+	"regexp"
+	"strings"
+)
+
+type Amend struct {
+	lib.AtDateArgs
+	Index   int      `name:"index" short:"i" help:"Select the entry by its position in the record (1 is the first)"`
+	Match   string   `name:"match" short:"m" help:"Select the first entry whose summary contains this text"`
+	Value   string   `name:"value" short:"v" help:"Replace the time value, e.g. 2h or 9:00-12:00"`
+	Summary string   `name:"summary" short:"s" help:"Replace the summary text"`
+	Tag     []string `name:"tag" help:"Add this tag to the summary"`
+	Untag   []string `name:"untag" help:"Remove this tag from the summary"`
+	Delete  bool     `name:"delete" help:"Remove the entry altogether"`
+	lib.NoStyleArgs
+	lib.OutputFileArgs
+}
+
+func (opt *Amend) Help() string {
+	return `The entry is either selected by its position in the record (--index) or by its summary (--match).
+All other lines of the file are left untouched.
+
+Examples:
+    klog amend --index=2 --value='9:00 - 12:30' file.klg
+    klog amend --match='meeting' --tag=jour_fixe file.klg
+    klog amend --date=2021-03-04 --index=1 --delete file.klg`
+}
+
+func (opt *Amend) Run(ctx app.Context) error {
+	opt.NoStyleArgs.Apply(&ctx)
+	if opt.Index == 0 && opt.Match == "" {
+		return errors.New("Please specify which entry to amend, either via --index or via --match")
+	}
+	for _, t := range append(append([]string{}, opt.Tag...), opt.Untag...) {
+		if !tagValuePattern.MatchString(t) {
+			return errors.New("Invalid tag: '" + t + "'")
+		}
+	}
+	date := opt.AtDate(ctx.Now())
+	matchEntry := func(i int, e Entry) bool {
+		if opt.Index != 0 {
+			return i == opt.Index-1
+		}
+		return strings.Contains(e.Summary().ToString(), opt.Match)
+	}
+	return lib.ReconcilerChain{
+		File: opt.OutputFileArgs.File,
+		Ctx:  ctx,
+	}.Apply(
+		func(pr *parser.ParseResult) (*parser.ReconcileResult, error) {
+			reconciler := parser.NewRecordReconciler(pr, func(r Record) bool {
+				return r.Date().IsEqualTo(date)
+			})
+			if reconciler == nil {
+				return nil, lib.NotEligibleError{}
+			}
+			if opt.Delete {
+				return reconciler.RemoveEntry(matchEntry)
+			}
+			return reconciler.UpdateEntry(matchEntry, opt.amend)
+		},
+	)
+}
+
+func (opt *Amend) amend(value string, summary Summary) (string, Summary) {
+	if opt.Value != "" {
+		value = sanitiseQuotedLeadingDash(opt.Value)
+	}
+	text := summary.ToString()
+	if opt.Summary != "" {
+		text = opt.Summary
+	}
+	for _, t := range opt.Untag {
+		text = removeTag(text, NewTag(t))
+	}
+	for _, t := range opt.Tag {
+		tag := NewTag(t)
+		if Summary(text).Tags()[tag] {
+			continue
+		}
+		if text != "" {
+			text += " "
+		}
+		text += tag.ToString()
+	}
+	return value, Summary(text)
+}
+
+var tagValuePattern = regexp.MustCompile(`^#?[\p{L}\d_]+$`)
+
+// removeTag removes all occurrences of the tag from the text, along with one
+// adjacent space each. The rest of the text is left untouched.
+func removeTag(text string, tag Tag) string {
+	matches := HashTagPattern.FindAllStringIndex(text, -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		start, end := matches[i][0], matches[i][1]
+		if NewTag(text[start:end]) != tag {
+			continue
+		}
+		if start > 0 && text[start-1] == ' ' {
+			start--
+		} else if end < len(text) && text[end] == ' ' {
+			end++
+		}
+		text = text[:start] + text[end:]
+	}
+	return text
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAmendEntryValueByIndex(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+1920-02-02
+Some summary
+	8:00 - 9:00 Foo
+	1h Bar
+`)._Run((&Amend{
+		AtDateArgs: lib.AtDateArgs{Date: klog.Ɀ_Date_(1920, 2, 2)},
+		Index:      2,
+		Value:      "1h30m",
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+1920-02-02
+Some summary
+	8:00 - 9:00 Foo
+	1h30m Bar
+`, state.writtenFileContents)
+}
+
+func TestAmendEntrySummaryAndTagsByMatch(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+1920-02-02
+	8:00 - 9:00 Meeting with #Alice
+	1h Call #bob
+`)._Run((&Amend{
+		AtDateArgs: lib.AtDateArgs{Date: klog.Ɀ_Date_(1920, 2, 2)},
+		Match:      "Call",
+		Tag:        []string{"#phone"},
+		Untag:      []string{"#BOB"},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+1920-02-02
+	8:00 - 9:00 Meeting with #Alice
+	1h Call #phone
+`, state.writtenFileContents)
+}
+
+func TestAmendReplacesSummary(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+1920-02-02
+	8:00 - ? Something
+`)._Run((&Amend{
+		AtDateArgs: lib.AtDateArgs{Date: klog.Ɀ_Date_(1920, 2, 2)},
+		Index:      1,
+		Summary:    "Something else",
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+1920-02-02
+	8:00 - ? Something else
+`, state.writtenFileContents)
+}
+
+func TestAmendDeletesEntry(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+1920-02-02
+	8:00 - 9:00 Foo
+	1h Bar
+`)._Run((&Amend{
+		AtDateArgs: lib.AtDateArgs{Date: klog.Ɀ_Date_(1920, 2, 2)},
+		Match:      "Foo",
+		Delete:     true,
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+1920-02-02
+	1h Bar
+`, state.writtenFileContents)
+}
+
+func TestAmendFailsIfNoEntrySelected(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+1920-02-02
+	1h Bar
+`)._Run((&Amend{
+		AtDateArgs: lib.AtDateArgs{Date: klog.Ɀ_Date_(1920, 2, 2)},
+		Value:      "2h",
+	}).Run)
+	require.Error(t, err)
+	assert.Equal(t, "", state.writtenFileContents)
+}
+
+func TestAmendFailsIfEntryDoesNotExist(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+1920-02-02
+	1h Bar
+`)._Run((&Amend{
+		AtDateArgs: lib.AtDateArgs{Date: klog.Ɀ_Date_(1920, 2, 2)},
+		Index:      5,
+		Value:      "2h",
+	}).Run)
+	require.Error(t, err)
+	assert.Equal(t, "", state.writtenFileContents)
+}
+
+func TestAmendRemovesTagWithoutAlteringOtherWhitespace(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+1920-02-02
+	1h #bob Call  with   Bob #bob
+`)._Run((&Amend{
+		AtDateArgs: lib.AtDateArgs{Date: klog.Ɀ_Date_(1920, 2, 2)},
+		Index:      1,
+		Untag:      []string{"bob"},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+1920-02-02
+	1h Call  with   Bob
+`, state.writtenFileContents)
+}
+
+func TestAmendRejectsInvalidTags(t *testing.T) {
+	for _, tag := range []string{"", "#", "foo bar"} {
+		_, err := NewTestingContext()._SetRecords(`
+1920-02-02
+	1h Call #bob
+`)._Run((&Amend{
+			AtDateArgs: lib.AtDateArgs{Date: klog.Ɀ_Date_(1920, 2, 2)},
+			Index:      1,
+			Untag:      []string{tag},
+		}).Run)
+		require.Error(t, err, tag)
+	}
+}
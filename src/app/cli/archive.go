@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/parser"
+	"github.com/jotaen/klog/src/parser/parsing"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type Archive struct {
+	Before Date `name:"before" help:"Archive all records before this date (default: January 1 of the current year)"`
+	lib.OutputFileArgs
+}
+
+func (opt *Archive) Help() string {
+	return `All records before the cutoff date are moved into archive files, with one archive file per calendar year.
+The archive files are located next to the original file, e.g. 'work.2021.klg' for 'work.klg'.
+If an archive file already exists, the records are inserted into it.
+
+Every archive file is bookmarked, e.g. as '@work-2021'.
+Commands that have filter flags (such as 'klog total' or 'klog report') automatically include the bookmarked archives, if the filter reaches into the respective years.`
+}
+
+func (opt *Archive) Run(ctx app.Context) error {
+	pr, source, err := ctx.ReadFileInput(opt.File)
+	if err != nil {
+		return err
+	}
+	if source == nil {
+		return errors.New("Please specify the file to archive")
+	}
+	cutoff := opt.Before
+	if cutoff == nil {
+		cutoff, _ = NewDate(ctx.Now().Year(), 1, 1)
+	}
+	remover := parser.NewRemovalReconciler(pr, func(r Record) bool {
+		return !r.Date().IsAfterOrEqual(cutoff)
+	})
+	if remover == nil {
+		ctx.Print("No records before " + cutoff.ToString() + " found.\n")
+		return nil
+	}
+
+	rs := remover.Records()
+	blocksByYear := make(map[int][][]parsing.Text)
+	recordsByYear := make(map[int][]Record)
+	for i, block := range remover.Blocks() {
+		year := rs[i].Date().Year()
+		blocksByYear[year] = append(blocksByYear[year], block)
+		recordsByYear[year] = append(recordsByYear[year], rs[i])
+	}
+	var years []int
+	for y := range blocksByYear {
+		years = append(years, y)
+	}
+	sort.Ints(years)
+
+	type archiveUpdate struct {
+		file         app.File
+		originalText string
+		newText      string
+	}
+	var updates []archiveUpdate
+	for _, year := range years {
+		archive := app.ArchiveFile(source, year)
+		archivePr, _, err := ctx.ReadFileInput(app.FileOrBookmarkName(archive.Path()))
+		if err != nil {
+			appErr, isAppErr := err.(app.Error)
+			if !isAppErr || appErr.Code() != app.NO_SUCH_FILE {
+				return err
+			}
+			// The new archive is formatted in the same way as the source.
+			archivePr = pr.Empty()
+		}
+		if collisions := findCollisions(recordsByYear[year], archivePr.Records); len(collisions) > 0 {
+			return errors.New("The archive " + archive.Path() + " already contains records at these dates: " + strings.Join(collisions, ", "))
+		}
+		update := archiveUpdate{archive, archivePr.Text(), archivePr.Text()}
+		for i, block := range blocksByYear[year] {
+			result, err := parser.NewBlockReconciler(archivePr, recordsByYear[year][i].Date()).InsertBlock(block)
+			if err != nil {
+				return err
+			}
+			update.newText = result.NewText
+			archivePr, _ = parser.Parse(update.newText)
+		}
+		updates = append(updates, update)
+	}
+	sourceResult, err := remover.RemoveRecords()
+	if err != nil {
+		return err
+	}
+
+	// Write the archives first, so that the records are never lost. If the
+	// source cannot be updated afterwards, the archives are reverted.
+	for _, u := range updates {
+		err = ctx.WriteFile(u.file, u.newText)
+		if err != nil {
+			return err
+		}
+	}
+	err = ctx.WriteFile(source, sourceResult.NewText)
+	if err != nil {
+		for _, u := range updates {
+			_ = ctx.WriteFile(u.file, u.originalText)
+		}
+		return err
+	}
+
+	name := archiveName(ctx, source)
+	err = ctx.ManipulateBookmarks(func(bc app.BookmarksCollection) app.Error {
+		for i, year := range years {
+			bc.Set(app.NewBookmark(app.ArchiveBookmarkName(name, year), updates[i].file))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i, year := range years {
+		ctx.Print(fmt.Sprintf(
+			"Archived %d record(s) to %s (%s)\n",
+			len(recordsByYear[year]),
+			updates[i].file.Path(),
+			app.NewName(app.ArchiveBookmarkName(name, year)).ValuePretty(),
+		))
+	}
+	return nil
+}
+
+// archiveName is the name of the bookmark that points to the file, or the
+// file name (without extension), if there is no such bookmark.
+func archiveName(ctx app.Context, f app.File) string {
+	stem := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+	bc, err := ctx.ReadBookmarks()
+	if err != nil {
+		return stem
+	}
+	for _, b := range bc.All() {
+		if b.Target().Path() == f.Path() && !b.IsDefault() {
+			return b.Name().Value()
+		}
+	}
+	return stem
+}
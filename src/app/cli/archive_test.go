@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchiveRecordsByYear(t *testing.T) {
+	ctx := NewTestingContext()._SetNow(2022, 3, 1, 0, 0)._SetFile("/files/work.klg", `
+2020-12-31
+	1h
+
+2021-01-01
+	2h
+
+2021-06-01
+	3h
+
+2022-01-01
+	4h
+`)._SetFile("/files/work.2021.klg", `2021-03-01
+	5h
+`)
+	state, err := ctx._Run((&Archive{OutputFileArgs: lib.OutputFileArgs{File: "/files/work.klg"}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2022-01-01
+	4h
+`, state.writtenFiles["/files/work.klg"])
+	assert.Equal(t, `2020-12-31
+	1h
+`, state.writtenFiles["/files/work.2020.klg"])
+	assert.Equal(t, `2021-01-01
+	2h
+
+2021-03-01
+	5h
+
+2021-06-01
+	3h
+`, state.writtenFiles["/files/work.2021.klg"])
+	assert.NotNil(t, ctx.bookmarks.Get(app.NewName("work-2020")))
+	assert.NotNil(t, ctx.bookmarks.Get(app.NewName("work-2021")))
+}
+
+func TestArchiveRecordsBeforeCutoff(t *testing.T) {
+	state, err := NewTestingContext()._SetFile("/work.klg", `
+2021-01-01
+	2h
+
+2021-06-01
+	3h
+`)._Run((&Archive{
+		Before:         klog.Ɀ_Date_(2021, 2, 1),
+		OutputFileArgs: lib.OutputFileArgs{File: "/work.klg"},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2021-06-01
+	3h
+`, state.writtenFiles["/work.klg"])
+	assert.Equal(t, `2021-01-01
+	2h
+`, state.writtenFiles["/work.2021.klg"])
+}
+
+func TestArchiveFailsOnDateCollision(t *testing.T) {
+	state, err := NewTestingContext()._SetFile("/work.klg", `
+2021-01-01
+	2h
+`)._SetFile("/work.2021.klg", `
+2021-01-01
+	2h
+`)._Run((&Archive{
+		Before:         klog.Ɀ_Date_(2022, 1, 1),
+		OutputFileArgs: lib.OutputFileArgs{File: "/work.klg"},
+	}).Run)
+	require.Error(t, err)
+	assert.Len(t, state.writtenFiles, 0)
+}
+
+func TestArchiveDoesNotRollBackIfCommitFails(t *testing.T) {
+	ctx, repo := newContextWithFailingGitCommit(t)
+	file := filepath.Join(repo, "work.klg")
+	require.Nil(t, os.WriteFile(file, []byte("2021-01-01\n\t2h\n\n2021-06-01\n\t3h\n"), 0600))
+
+	err := (&Archive{
+		Before:         klog.Ɀ_Date_(2021, 2, 1),
+		OutputFileArgs: lib.OutputFileArgs{File: app.FileOrBookmarkName(file)},
+	}).Run(ctx)
+	require.Nil(t, err)
+	contents, _ := os.ReadFile(file)
+	assert.Equal(t, "2021-06-01\n\t3h\n", string(contents))
+	archiveContents, _ := os.ReadFile(filepath.Join(repo, "work.2021.klg"))
+	assert.Equal(t, "2021-01-01\n\t2h\n", string(archiveContents))
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/lib/jotaen/terminalformat"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/app/cli/report"
+	"github.com/jotaen/klog/src/service"
+)
+
+type Balance struct {
+	AggregateBy string     `name:"aggregate" short:"a" help:"Aggregate data by: day, week, month, quarter, year" enum:"DAY,day,d,WEEK,week,w,MONTH,month,m,QUARTER,quarter,q,YEAR,year,y," default:"month"`
+	Since       Date       `name:"since" help:"Only show the balance since this date (inclusive)"`
+	Until       Date       `name:"until" help:"Only show the balance until this date (inclusive)"`
+	Period      lib.Period `name:"period" help:"Only show the balance in this period (YYYY-MM or YYYY)"`
+	lib.NoStyleArgs
+	lib.InputFilesArgs
+}
+
+func (opt *Balance) Help() string {
+	return `The balance is the accumulated difference between total and should-total time, in chronological order.
+The flags --since, --until and --period only restrict which rows are displayed.
+The balance itself is always based on all records (including bookmarked archives).
+
+The balance can be configured in the "balance" section of ~/.klog/config.json:
+    {
+      "balance": {
+        "initial": "12h30m",
+        "carry_over": {"period": "quarter", "max": "40h"},
+        "corrections": [{"date": "2021-06-30", "value": "-10h", "note": "Payout"}]
+      }
+    }
+
+At the end of every carry-over period (month, quarter or year), the balance is capped to the maximum,
+or it is set to 0 altogether with "reset": true.
+Days without records count as owed time, if the work schedule in the "schedule" section requires it.`
+}
+
+func (opt *Balance) Run(ctx app.Context) error {
+	opt.NoStyleArgs.Apply(&ctx)
+	ctx.IncludeArchives(func(int) bool { return true })
+	records, err := ctx.ReadInputs(opt.File...)
+	if err != nil {
+		return err
+	}
+	config, err := ctx.ReadConfig(opt.File...)
+	if err != nil {
+		return err
+	}
+	steps := service.RunningBalance(config.Balance, config.Schedule, records...)
+
+	aggregator := newAggregator(opt.AggregateBy, nil)
+	type row struct {
+		date       Date
+		diff       Duration
+		adjustment Duration
+		balance    Duration
+	}
+	var rows []*row
+	rowsByHash := make(map[report.Hash]*row)
+	for _, step := range steps {
+		if !opt.isInRange(step.Date) {
+			continue
+		}
+		hash := aggregator.DateHash(step.Date)
+		r := rowsByHash[hash]
+		if r == nil {
+			r = &row{step.Date, NewDuration(0, 0), NewDuration(0, 0), nil}
+			rowsByHash[hash] = r
+			rows = append(rows, r)
+		}
+		r.diff = r.diff.Plus(step.Diff)
+		r.adjustment = r.adjustment.Plus(step.Adjustment)
+		r.balance = step.Balance
+	}
+
+	table := terminalformat.NewTable(aggregator.NumberOfPrefixColumns()+3, " ")
+	aggregator.OnHeaderPrefix(table)
+	table.CellR("    Diff").CellR("  Adjust.").CellR(" Balance")
+	grandDiff := NewDuration(0, 0)
+	grandAdjustment := NewDuration(0, 0)
+	for _, r := range rows {
+		aggregator.OnRowPrefix(table, r.date)
+		table.CellR(ctx.Serialiser().SignedDuration(r.diff))
+		if r.adjustment.InMinutes() != 0 {
+			table.CellR(ctx.Serialiser().SignedDuration(r.adjustment))
+		} else {
+			table.Skip(1)
+		}
+		table.CellR(ctx.Serialiser().SignedDuration(r.balance))
+		grandDiff = grandDiff.Plus(r.diff)
+		grandAdjustment = grandAdjustment.Plus(r.adjustment)
+	}
+	table.Skip(aggregator.NumberOfPrefixColumns()).Fill("=").Fill("=").Fill("=")
+	table.Skip(aggregator.NumberOfPrefixColumns())
+	table.CellR(ctx.Serialiser().SignedDuration(grandDiff))
+	table.CellR(ctx.Serialiser().SignedDuration(grandAdjustment))
+	if len(rows) > 0 {
+		table.CellR(ctx.Serialiser().SignedDuration(rows[len(rows)-1].balance))
+	} else {
+		table.CellR(ctx.Serialiser().SignedDuration(NewDuration(0, 0)))
+	}
+	table.Collect(ctx.Print)
+	return nil
+}
+
+func (opt *Balance) isInRange(d Date) bool {
+	since, until := opt.Since, opt.Until
+	if opt.Period.Since != nil {
+		since, until = opt.Period.Since, opt.Period.Until
+	}
+	if since != nil && !d.IsAfterOrEqual(since) {
+		return false
+	}
+	if until != nil && !until.IsAfterOrEqual(d) {
+		return false
+	}
+	return true
+}
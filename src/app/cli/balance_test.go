@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBalanceByMonth(t *testing.T) {
+	state, err := NewTestingContext()._SetConfig(app.Config{
+		Balance: &service.BalanceRules{
+			Initial:     klog.NewDuration(1, 0),
+			Corrections: []service.BalanceCorrection{{Date: klog.Ɀ_Date_(2018, 8, 1), Value: klog.NewDuration(-2, 0)}},
+		},
+	})._SetRecords(`
+2018-07-07 (8h!)
+	9h
+
+2018-07-08 (5h30m!)
+	2h
+
+2018-08-09 (2h!)
+	5h20m
+`)._Run((&Balance{AggregateBy: "month"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+             Diff   Adjust.  Balance
+2018 Jul   -2h30m             -1h30m
+     Aug   +3h20m       -2h     -10m
+         ======== ========= ========
+             +50m       -2h     -10m
+`, state.printBuffer)
+}
+
+func TestBalanceInPeriod(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2018-07-07 (8h!)
+	9h
+
+2018-07-08 (5h30m!)
+	2h
+`)._Run((&Balance{AggregateBy: "day", Since: klog.Ɀ_Date_(2018, 7, 8)}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+                        Diff   Adjust.  Balance
+2018 Jul    Sun  8.   -3h30m             -2h30m
+                    ======== ========= ========
+                      -3h30m        0m   -2h30m
+`, state.printBuffer)
+}
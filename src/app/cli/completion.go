@@ -0,0 +1,344 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/alecthomas/kong"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/service"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type Completion struct {
+	Shell string `arg required name:"shell" help:"The shell: bash, zsh, fish" enum:"bash,zsh,fish"`
+}
+
+func (opt *Completion) Help() string {
+	return `Prints a script that enables tab completion of commands, flags, bookmarks, tags and template names.
+
+To enable the completion, add the respective line to the config file of your shell.
+
+Bash (~/.bashrc): source <(klog completion bash)
+
+Zsh (~/.zshrc): source <(klog completion zsh)
+
+Fish (~/.config/fish/config.fish): klog completion fish | source`
+}
+
+func (opt *Completion) Run(ctx app.Context, k *kong.Context) error {
+	root := newCompletionCommand(k.Model.Node, "klog", k.Model.Flags)
+	switch opt.Shell {
+	case "fish":
+		ctx.Print(fishCompletionScript(root))
+	case "zsh":
+		ctx.Print("#compdef klog\n" +
+			"autoload -U +X bashcompinit && bashcompinit\n" +
+			bashCompletionScript(root))
+	default:
+		ctx.Print(bashCompletionScript(root))
+	}
+	return nil
+}
+
+// Complete is the endpoint that the completion scripts invoke to retrieve
+// the values that cannot be known upfront.
+type Complete struct {
+	Kind string `arg required name:"kind" enum:"bookmarks,tags,templates"`
+}
+
+func (opt *Complete) Run(ctx app.Context) error {
+	var values []string
+	switch opt.Kind {
+	case "bookmarks":
+		bc, err := ctx.ReadBookmarks()
+		if err != nil {
+			return nil
+		}
+		for _, b := range bc.All() {
+			values = append(values, b.Name().ValuePretty())
+		}
+	case "tags":
+		pr, _, err := ctx.ReadFileInput("")
+		if err != nil || pr == nil {
+			return nil
+		}
+		entriesByTag, _ := service.EntryTagLookup(pr.Records...)
+		for t := range entriesByTag {
+			// The tags are suggested without the `#`, since shells would
+			// otherwise treat the value as comment.
+			values = append(values, string(t))
+		}
+	case "templates":
+		values = ctx.TemplateNames()
+	}
+	sort.Strings(values)
+	for _, v := range values {
+		ctx.Print(v + "\n")
+	}
+	return nil
+}
+
+// completionValues describes how the value of a flag or argument is completed.
+type completionValues struct {
+	words   []string // A fixed set of values, e.g. for enums
+	dynamic string   // The kind of values that `klog __complete` provides
+	files   bool     // Whether file names are suggested
+}
+
+type completionFlag struct {
+	long       string
+	short      rune
+	help       string
+	hidden     bool // Hidden flags are not suggested, but their values are
+	takesValue bool
+	values     completionValues
+}
+
+type completionCommand struct {
+	path        string
+	names       []string // The name and the aliases of the command
+	help        string
+	hidden      bool
+	subcommands []*completionCommand
+	flags       []completionFlag
+	args        *completionValues // Nil, if the command doesn’t take arguments
+}
+
+func newCompletionCommand(n *kong.Node, path string, globalFlags []*kong.Flag) *completionCommand {
+	cmd := &completionCommand{
+		path:   path,
+		names:  append([]string{n.Name}, n.Aliases...),
+		help:   n.Help,
+		hidden: n.Hidden,
+	}
+	flags := n.Flags
+	if n.Type != kong.ApplicationNode {
+		flags = append(append([]*kong.Flag{}, globalFlags...), n.Flags...)
+	}
+	for _, f := range flags {
+		cmd.flags = append(cmd.flags, completionFlag{
+			long:       f.Name,
+			short:      f.Short,
+			help:       f.Help,
+			hidden:     f.Hidden,
+			takesValue: !f.IsBool(),
+			values:     newCompletionValues(f.Value),
+		})
+	}
+	for _, p := range n.Positional {
+		values := newCompletionValues(p)
+		if cmd.args == nil || values.files {
+			cmd.args = &values
+		}
+	}
+	for _, c := range n.Children {
+		if c.Type != kong.CommandNode {
+			continue
+		}
+		cmd.subcommands = append(cmd.subcommands, newCompletionCommand(c, path+" "+c.Name, globalFlags))
+	}
+	return cmd
+}
+
+func newCompletionValues(v *kong.Value) completionValues {
+	fileType := reflect.TypeOf(app.FileOrBookmarkName(""))
+	t := v.Target.Type()
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	switch {
+	case t == fileType:
+		return completionValues{dynamic: "bookmarks", files: true}
+	case v.Name == "tag":
+		return completionValues{dynamic: "tags"}
+	case v.Name == "template":
+		return completionValues{dynamic: "templates"}
+	case v.Enum != "":
+		var words []string
+		enum := v.EnumMap()
+		for _, w := range strings.Split(v.Enum, ",") {
+			// Upper-case variants are omitted if there is a lower-case one.
+			if w == "" || (w != strings.ToLower(w) && enum[strings.ToLower(w)]) {
+				continue
+			}
+			words = append(words, w)
+		}
+		return completionValues{words: words}
+	}
+	return completionValues{}
+}
+
+// visit calls the function for the command and all its (nested) subcommands.
+func (c *completionCommand) visit(f func(*completionCommand)) {
+	f(c)
+	for _, s := range c.subcommands {
+		s.visit(f)
+	}
+}
+
+func bashCompletionScript(root *completionCommand) string {
+	commandCases := ""
+	flagCases := ""
+	replyCases := ""
+	root.visit(func(c *completionCommand) {
+		for _, s := range c.subcommands {
+			for _, name := range s.names {
+				commandCases += fmt.Sprintf("\t\t\t\"%s %s\") path=\"%s\" ;;\n", c.path, name, s.path)
+			}
+		}
+		var flagWords []string
+		for _, f := range c.flags {
+			if !f.hidden {
+				flagWords = append(flagWords, "--"+f.long)
+			}
+			if !f.takesValue {
+				continue
+			}
+			pattern := fmt.Sprintf("\"%s --%s\"", c.path, f.long)
+			if f.short != 0 {
+				pattern += fmt.Sprintf("|\"%s -%c\"", c.path, f.short)
+			}
+			flagCases += fmt.Sprintf("\t\t%s) %s; return ;;\n", pattern, bashReply(f.values))
+		}
+		var args completionValues
+		if c.args != nil {
+			args = *c.args
+		}
+		for _, s := range c.subcommands {
+			if !s.hidden {
+				args.words = append(args.words, s.names[0])
+			}
+		}
+		replyCases += fmt.Sprintf("\t\t\"%s\")\n", c.path)
+		replyCases += fmt.Sprintf("\t\t\tif [[ \"$cur\" == -* ]]; then %s; else %s; fi ;;\n",
+			bashReply(completionValues{words: flagWords}), bashReply(args))
+	})
+	return `# bash completion for klog
+__klog_reply() {
+	# $1: words, $2: kind of values that klog provides, $3: whether to suggest files
+	local words="$1"
+	if [[ -n "$2" ]]; then
+		words="$words $(klog __complete "$2" 2>/dev/null)"
+	fi
+	COMPREPLY=($(compgen -W "$words" -- "$cur"))
+	if [[ -n "$3" ]]; then
+		COMPREPLY+=($(compgen -f -- "$cur"))
+	fi
+	# Bash splits words at characters like '@', so the part in front of
+	# that must be removed from the suggestions.
+	if [[ -z "${ZSH_VERSION-}" ]]; then
+		local trimmed="${cur##*[@=:]}"
+		local prefix="${cur:0:${#cur}-${#trimmed}}"
+		if [[ -n "$prefix" ]]; then
+			COMPREPLY=("${COMPREPLY[@]#"$prefix"}")
+		fi
+	fi
+}
+
+_klog() {
+	local line="${COMP_LINE:0:COMP_POINT}"
+	local -a words
+	read -ra words <<< "$line"
+	local cur="" prev=""
+	if [[ "$line" == *[[:space:]] ]]; then
+		prev="${words[${#words[@]}-1]}"
+	else
+		cur="${words[${#words[@]}-1]}"
+		prev="${words[${#words[@]}-2]}"
+		unset 'words[${#words[@]}-1]'
+	fi
+	if [[ "$cur" == --*=* ]]; then
+		prev="${cur%%=*}"
+		cur="${cur#*=}"
+	fi
+	local path="klog" i
+	for ((i = 1; i < ${#words[@]}; i++)); do
+		case "$path ${words[i]}" in
+` + commandCases + `		esac
+	done
+	case "$path $prev" in
+` + flagCases + `	esac
+	case "$path" in
+` + replyCases + `	esac
+}
+
+complete -o default -F _klog klog
+`
+}
+
+func bashReply(v completionValues) string {
+	files := ""
+	if v.files {
+		files = "1"
+	}
+	return fmt.Sprintf("__klog_reply \"%s\" \"%s\" \"%s\"", strings.Join(v.words, " "), v.dynamic, files)
+}
+
+func fishCompletionScript(root *completionCommand) string {
+	script := `# fish completion for klog
+function __klog_path
+	set -l path klog
+	for word in (commandline -opc)[2..-1]
+		switch "$path $word"
+`
+	root.visit(func(c *completionCommand) {
+		for _, s := range c.subcommands {
+			for _, name := range s.names {
+				script += fmt.Sprintf("\t\t\tcase '%s %s'\n\t\t\t\tset path '%s'\n", c.path, name, s.path)
+			}
+		}
+	})
+	script += `		end
+	end
+	echo $path
+end
+
+complete -c klog -f
+`
+	root.visit(func(c *completionCommand) {
+		condition := fmt.Sprintf("-n 'test (__klog_path) = \"%s\"'", c.path)
+		for _, s := range c.subcommands {
+			if !s.hidden {
+				script += fmt.Sprintf("complete -c klog %s -a '%s' -d '%s'\n", condition, s.names[0], fishQuote(s.help))
+			}
+		}
+		if c.args != nil && (c.args.files || c.args.dynamic != "" || len(c.args.words) > 0) {
+			script += fmt.Sprintf("complete -c klog %s%s\n", condition, fishValues(*c.args))
+		}
+		for _, f := range c.flags {
+			short := ""
+			if f.short != 0 {
+				short = fmt.Sprintf(" -s %c", f.short)
+			}
+			if f.hidden {
+				continue
+			}
+			values := ""
+			if f.takesValue {
+				values = " -r" + fishValues(f.values)
+			}
+			script += fmt.Sprintf("complete -c klog %s -l %s%s%s -d '%s'\n", condition, f.long, short, values, fishQuote(f.help))
+		}
+	})
+	return script
+}
+
+func fishValues(v completionValues) string {
+	result := ""
+	if v.files {
+		result += " -F"
+	}
+	words := strings.Join(v.words, " ")
+	if v.dynamic != "" {
+		words = strings.TrimSpace(words + " (klog __complete " + v.dynamic + " 2>/dev/null)")
+	}
+	if words != "" {
+		result += " -a '" + words + "'"
+	}
+	return result
+}
+
+func fishQuote(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(text)
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"github.com/alecthomas/kong"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newCompletionModel(t *testing.T) *completionCommand {
+	k, err := kong.New(&Cli{}, append([]kong.Option{kong.Name("klog")}, lib.TypeMappers()...)...)
+	require.Nil(t, err)
+	return newCompletionCommand(k.Model.Node, "klog", k.Model.Flags)
+}
+
+func TestCompleteBookmarks(t *testing.T) {
+	ctx := NewTestingContext()
+	ctx.bookmarks.Set(app.NewBookmark("work", app.NewFileOrPanic("/work.klg")))
+	ctx.bookmarks.Set(app.NewBookmark("sports", app.NewFileOrPanic("/sports.klg")))
+	state, err := ctx._Run((&Complete{Kind: "bookmarks"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n@sports\n@work\n", state.printBuffer)
+}
+
+func TestCompleteTags(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2020-01-01
+Work #project
+    1h #meeting
+    2h #coding
+
+2020-01-02
+    1h #meeting
+`)._Run((&Complete{Kind: "tags"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\ncoding\nmeeting\nproject\n", state.printBuffer)
+}
+
+func TestCompleteTemplates(t *testing.T) {
+	state, err := NewTestingContext().
+		_SetTemplate("weekly", "{{ TODAY }}")._SetTemplate("daily", "{{ TODAY }}").
+		_Run((&Complete{Kind: "templates"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\ndaily\nweekly\n", state.printBuffer)
+}
+
+func TestBashCompletionScript(t *testing.T) {
+	script := bashCompletionScript(newCompletionModel(t))
+	assert.Contains(t, script, "\t\t\t\"klog in\") path=\"klog start\" ;;\n")
+	assert.Contains(t, script, "\t\t\t\"klog bookmarks set\") path=\"klog bookmarks set\" ;;\n")
+	assert.Contains(t, script, "\t\t\"klog print --tag\") __klog_reply \"\" \"tags\" \"\"; return ;;\n")
+	assert.Contains(t, script, "\t\t\"klog create --template\") __klog_reply \"\" \"templates\" \"\"; return ;;\n")
+	assert.Contains(t, script, "\t\t\"klog report --aggregate\"|\"klog report -a\") __klog_reply \"day d week w month m quarter q year y\" \"\" \"\"; return ;;\n")
+	assert.Contains(t, script, "\t\t\"klog bookmarks\")\n\t\t\tif [[ \"$cur\" == -* ]]; then __klog_reply \"--help --errors\" \"\" \"\"; else __klog_reply \"list set unset clear\" \"\" \"\"; fi ;;\n")
+	assert.Contains(t, script, "\t\t\"klog total\")\n\t\t\tif [[ \"$cur\" == -* ]]; then __klog_reply \"--help --errors ")
+	assert.Contains(t, script, "; else __klog_reply \"\" \"bookmarks\" \"1\"; fi ;;\n")
+}
+
+func TestFishCompletionScript(t *testing.T) {
+	script := fishCompletionScript(newCompletionModel(t))
+	assert.Contains(t, script, "\t\t\tcase 'klog in'\n\t\t\t\tset path 'klog start'\n")
+	assert.Contains(t, script, "complete -c klog -n 'test (__klog_path) = \"klog\"' -a 'print' -d 'Pretty-prints records'\n")
+	assert.Contains(t, script, "complete -c klog -n 'test (__klog_path) = \"klog print\"' -F -a '(klog __complete bookmarks 2>/dev/null)'\n")
+	assert.Contains(t, script, "complete -c klog -n 'test (__klog_path) = \"klog print\"' -l tag -r -a '(klog __complete tags 2>/dev/null)' -d 'Only records (or particular entries) that match this tag'\n")
+	assert.Contains(t, script, "complete -c klog -n 'test (__klog_path) = \"klog report\"' -l aggregate -s a -r -a 'day d week w month m quarter q year y' -d ")
+	assert.NotContains(t, script, "-a 'ls'")
+	assert.Contains(t, script, "complete -c klog -n 'test (__klog_path) = \"klog create\"' -l template -r -a '(klog __complete templates 2>/dev/null)' -d ")
+}
@@ -9,8 +9,9 @@ import (
 )
 
 type Create struct {
-	Template    string   `name:"template" hidden help:"The name of the template to instantiate"`
-	ShouldTotal Duration `name:"should" help:"The should-total of the record"`
+	Template    string            `name:"template" help:"The name of the template to instantiate (see 'klog templates')"`
+	Vars        map[string]string `name:"var" help:"A value for a template parameter, as NAME=VALUE"`
+	ShouldTotal Duration          `name:"should" help:"The should-total of the record"`
 	lib.AtDateArgs
 	lib.NoStyleArgs
 	lib.OutputFileArgs
@@ -18,7 +19,11 @@ type Create struct {
 
 func (opt *Create) Help() string {
 	return `The new record is inserted into the file at the chronologically correct position.
-(Assuming that the records are sorted from oldest to latest.)`
+(Assuming that the records are sorted from oldest to latest.)
+
+With --template, the record is created from a template. See 'klog templates --help' for how templates work.
+
+Pass - instead of a file to read the records from stdin and to print the result to stdout.`
 }
 
 func (opt *Create) Run(ctx app.Context) error {
@@ -26,9 +31,19 @@ func (opt *Create) Run(ctx app.Context) error {
 	date := opt.AtDate(ctx.Now())
 	lines, err := func() ([]parsing.Text, error) {
 		if opt.Template != "" {
-			return ctx.InstantiateTemplate(opt.Template)
+			// The schedule is only needed for {{ SHOULD }}, so a broken config
+			// doesn’t prevent the template from being used.
+			var should ShouldTotal
+			if config, cErr := ctx.ReadConfig(opt.File); cErr == nil && config.Schedule != nil {
+				should = config.Schedule.ShouldTotalAt(date)
+			}
+			variables := parser.TemplateBuiltins(date, ctx.Now(), should)
+			for name, value := range opt.Vars {
+				variables[name] = value
+			}
+			return ctx.InstantiateTemplate(opt.Template, variables)
 		}
-		headline := opt.AtDate(ctx.Now()).ToString()
+		headline := date.ToString()
 		if opt.ShouldTotal != nil {
 			headline += " (" + opt.ShouldTotal.ToString() + "!)"
 		}
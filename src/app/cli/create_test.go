@@ -2,7 +2,10 @@ package cli
 
 import (
 	"github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
 	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/parser/parsing"
+	"github.com/jotaen/klog/src/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -70,3 +73,69 @@ func TestCreateWithValues(t *testing.T) {
 1976-01-02 (5h55m!)
 `, state.writtenFileContents)
 }
+
+func TestCreateFromTemplate(t *testing.T) {
+	state, err := NewTestingContext()._SetConfig(app.Config{
+		Schedule: &service.Schedule{Periods: []service.SchedulePeriod{{
+			Since: klog.Ɀ_Date_(1920, 1, 1),
+			Hours: map[int]klog.Duration{2: klog.NewDuration(6, 15)},
+		}}},
+	})._SetRecords(`
+1920-02-01
+	4h33m
+`)._SetNow(1920, 2, 3, 15, 24).
+		_SetTemplate("daily", "{{ TODAY }} ({{ SHOULD }})\n{{ WEEKDAY }} #{{ project = foo }}\n    {{ start }} - ?\n").
+		_Run((&Create{Template: "daily", Vars: map[string]string{"start": "8:00"}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+1920-02-01
+	4h33m
+
+1920-02-03 (6h15m!)
+Tuesday #foo
+	8:00 - ?
+`, state.writtenFileContents)
+}
+
+func TestCreateFromTemplateWithoutSchedule(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords("")._SetNow(1920, 2, 3, 15, 24).
+		_SetTemplate("daily", "{{ TODAY }}\nShould: [{{ SHOULD }}]\n").
+		_Run((&Create{Template: "daily"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "1920-02-03\nShould: []\n", state.writtenFileContents)
+}
+
+func TestCreateFromTemplateIgnoresBrokenConfig(t *testing.T) {
+	state, err := NewTestingContext()._SetConfigError(
+		app.NewErrorWithCode(app.CONFIG_ERROR, "Invalid config", "", nil),
+	)._SetRecords("")._SetNow(1920, 2, 3, 15, 24).
+		_SetTemplate("daily", "{{ TODAY }}\n    {{ start = 8:00 }} - ?\n").
+		_Run((&Create{Template: "daily"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "1920-02-03\n    8:00 - ?\n", state.writtenFileContents)
+}
+
+func TestCreateFromTemplateAtOtherDate(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+1920-02-01
+	4h33m
+`)._SetNow(1920, 2, 3, 15, 24).
+		_SetTemplate("daily", "{{ TODAY }}\n{{ WEEKDAY }} in week {{ WEEK }}, after {{ YESTERDAY }}\n").
+		_Run((&Create{Template: "daily", AtDateArgs: lib.AtDateArgs{Date: klog.Ɀ_Date_(1920, 2, 8)}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+1920-02-01
+	4h33m
+
+1920-02-08
+Sunday in week 6, after 1920-02-07
+`, state.writtenFileContents)
+}
+
+func TestCreateFromTemplateFailsIfValueIsMissing(t *testing.T) {
+	_, err := NewTestingContext()._SetRecords("")._SetNow(1920, 2, 3, 15, 24).
+		_SetTemplate("daily", "{{ TODAY }}\n    {{ start }} - ?\n").
+		_Run((&Create{Template: "daily"}).Run)
+	require.Error(t, err)
+	assert.Equal(t, "ErrorMissingTemplateValue", err.(parsing.Errors).Get()[0].Code())
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/parser"
+	"strings"
+)
+
+type Delete struct {
+	lib.FilterArgs
+	DryRun bool `name:"dry-run" help:"Only show which records would be deleted, without deleting them"`
+	Yes    bool `name:"yes" short:"y" help:"Skip confirmation"`
+	lib.NoStyleArgs
+	lib.OutputFileArgs
+}
+
+func (opt *Delete) Help() string {
+	return `All records that match the filter are removed from the file, including their summaries and entries.
+(When filtering by tag, the entire record is removed, not just the matching entries.)
+
+The remainder of the file is left untouched.`
+}
+
+func (opt *Delete) Run(ctx app.Context) error {
+	opt.NoStyleArgs.Apply(&ctx)
+	if opt.FilterArgs.IsEmpty() {
+		return errors.New("Please specify which records to delete, e.g. via --date or --period")
+	}
+	pr, target, err := ctx.ReadFileInput(opt.File)
+	if err != nil {
+		return err
+	}
+	now := ctx.Now()
+	reconciler := parser.NewRemovalReconciler(pr, func(r Record) bool {
+		return opt.FilterArgs.IsMatch(now, r)
+	})
+	if reconciler == nil {
+		ctx.Print("No matching records found.\n")
+		return nil
+	}
+	rs := reconciler.Records()
+	ctx.Print("\n" + ctx.Serialiser().SerialiseRecords(rs...) + "\n")
+	result, err := reconciler.RemoveRecords()
+	if err != nil {
+		return err
+	}
+	count := fmt.Sprintf("%d record%s", len(rs), func() string {
+		if len(rs) == 1 {
+			return ""
+		}
+		return "s"
+	}())
+	if opt.DryRun {
+		ctx.Print("Dry run: " + count + " would be deleted.\n")
+		return nil
+	}
+	if !opt.Yes {
+		ctx.Print("Do you want to delete " + count + "? [y/N] ")
+		confirmation, err := ctx.ReadLine()
+		if err != nil {
+			return err
+		}
+		if strings.ToLower(confirmation) != "y" {
+			return nil
+		}
+	}
+	err = ctx.WriteFile(target, result.NewText)
+	if err != nil {
+		return err
+	}
+	ctx.Print("Deleted " + count + ".\n")
+	return nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDeleteRecords(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2000-01-01
+	1h
+
+2000-01-02
+	2h #test
+
+2000-01-03
+	3h
+`)._Run((&Delete{
+		FilterArgs: lib.FilterArgs{Tags: []string{"test"}},
+		Yes:        true,
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2000-01-01
+	1h
+
+2000-01-03
+	3h
+`, state.writtenFileContents)
+}
+
+func TestDeleteRecordsInDryRun(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2000-01-01
+	1h
+
+2000-01-02
+	2h
+`)._Run((&Delete{
+		FilterArgs: lib.FilterArgs{Since: klog.Ɀ_Date_(2000, 1, 2)},
+		DryRun:     true,
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "", state.writtenFileContents)
+	assert.Equal(t, `
+2000-01-02
+    2h
+
+Dry run: 1 record would be deleted.
+`, state.printBuffer)
+}
+
+func TestDeleteRequiresConfirmation(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2000-01-01
+	1h
+`)._Run((&Delete{
+		FilterArgs: lib.FilterArgs{Date: []klog.Date{klog.Ɀ_Date_(2000, 1, 1)}},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "", state.writtenFileContents)
+}
+
+func TestDeleteRequiresFilter(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2000-01-01
+	1h
+`)._Run((&Delete{Yes: true}).Run)
+	require.Error(t, err)
+	assert.Equal(t, "", state.writtenFileContents)
+}
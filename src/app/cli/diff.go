@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"errors"
+	"github.com/jotaen/klog/lib/jotaen/terminalformat"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/parser"
+	"github.com/jotaen/klog/src/service"
+	"strings"
+)
+
+type Diff struct {
+	Old app.FileOrBookmarkName `arg type:"string" name:"old" help:"Old version: .klg file, bookmark, or git revision (rev:path)"`
+	New app.FileOrBookmarkName `arg optional type:"string" name:"new" help:"New version: .klg file, bookmark, or git revision (rev:path)"`
+	lib.NoStyleArgs
+}
+
+func (opt *Diff) Help() string {
+	return `Compares two versions of records, and prints the added, removed and changed records and entries.
+Other than a textual diff, the comparison ignores formatting, and it shows how the totals have changed, per record and per tag.
+
+A version can be read from the local git history via the syntax rev:path, e.g. HEAD~1:times.klg or main:@work.
+If only one version is given as git revision, it’s compared with the current state of the file.
+
+Examples:
+    klog diff old.klg new.klg
+    klog diff HEAD~1:times.klg
+    klog diff main:times.klg feature:times.klg`
+}
+
+func (opt *Diff) Run(ctx app.Context) error {
+	opt.NoStyleArgs.Apply(&ctx)
+	newArg := opt.New
+	if newArg == "" {
+		revision, path := splitRevision(opt.Old)
+		if revision == "" {
+			return errors.New("Please specify two versions to compare, or a git revision, e.g. HEAD:times.klg")
+		}
+		newArg = path
+	}
+	oldRecords, err := readVersion(ctx, opt.Old)
+	if err != nil {
+		return err
+	}
+	newRecords, err := readVersion(ctx, newArg)
+	if err != nil {
+		return err
+	}
+
+	changes := service.CompareRecords(oldRecords, newRecords)
+	if len(changes) == 0 {
+		ctx.Print("No differences\n")
+		return nil
+	}
+	s := ctx.Serialiser()
+	format := func(marker string, color string) string {
+		if opt.IsStyled() {
+			return terminalformat.Style{Color: color}.Format(marker)
+		}
+		return marker
+	}
+	added, removed := format("+", "120"), format("-", "167")
+	for _, c := range changes {
+		marker := map[service.ChangeType]string{
+			service.RECORD_ADDED:   added,
+			service.RECORD_REMOVED: removed,
+			service.RECORD_CHANGED: "~",
+		}[c.Type]
+		ctx.Print(marker + " " + s.Date(c.Date) + "  " + s.SignedDuration(c.TotalDelta) + "\n")
+		for _, e := range c.RemovedEntries {
+			ctx.Print("    " + removed + " " + serialiseEntry(s, e) + "\n")
+		}
+		for _, e := range c.AddedEntries {
+			ctx.Print("    " + added + " " + serialiseEntry(s, e) + "\n")
+		}
+	}
+
+	oldTags, _ := service.EntryTagLookup(oldRecords...)
+	newTags, _ := service.EntryTagLookup(newRecords...)
+	allTags := make(map[Tag][]Entry)
+	for t := range oldTags {
+		allTags[t] = nil
+	}
+	for t := range newTags {
+		allTags[t] = nil
+	}
+	table := terminalformat.NewTable(2, " ")
+	hasTagChanges := false
+	for _, t := range sortTags(allTags) {
+		delta := service.TotalEntries(newTags[t]...).Minus(service.TotalEntries(oldTags[t]...))
+		if delta.InMinutes() == 0 {
+			continue
+		}
+		hasTagChanges = true
+		table.CellL(t.ToString()).CellL(s.SignedDuration(delta))
+	}
+	if hasTagChanges {
+		ctx.Print("\nTags:\n")
+		table.Collect(ctx.Print)
+	}
+
+	totalDelta := service.Total(newRecords...).Minus(service.Total(oldRecords...))
+	ctx.Print("\nTotal: " + s.SignedDuration(totalDelta) + "\n")
+	return nil
+}
+
+// splitRevision splits the `rev:path` syntax into the git revision and the
+// file or bookmark. The revision is empty if the argument is a plain file.
+func splitRevision(arg app.FileOrBookmarkName) (string, app.FileOrBookmarkName) {
+	i := strings.Index(string(arg), ":")
+	if i <= 1 {
+		// A single character can also be a drive letter on Windows, e.g. `C:\`
+		return "", arg
+	}
+	return string(arg)[:i], arg[i+1:]
+}
+
+func readVersion(ctx app.Context, arg app.FileOrBookmarkName) ([]Record, error) {
+	revision, path := splitRevision(arg)
+	if revision != "" {
+		pr, _, err := ctx.ReadFileRevision(revision, path)
+		if err != nil {
+			return nil, err
+		}
+		return pr.Records, nil
+	}
+	pr, _, err := ctx.ReadFileInput(path)
+	if err != nil {
+		return nil, err
+	}
+	return pr.Records, nil
+}
+
+func serialiseEntry(s *parser.Serialiser, e Entry) string {
+	text := e.Unbox(
+		func(r Range) interface{} { return s.Range(r) },
+		func(d Duration) interface{} { return s.Duration(d) },
+		func(o OpenRange) interface{} { return s.OpenRange(o) },
+	).(string)
+	if e.Summary() != "" {
+		// Continuation lines of the summary are joined into one line.
+		text += " " + s.Summary(Summary(strings.ReplaceAll(e.Summary().ToString(), "\n", " ")))
+	}
+	return text
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDiffPrintsChangedRecordsAndTotals(t *testing.T) {
+	state, err := NewTestingContext()._SetFile("/old.klg", `
+2000-01-01
+	2h #foo
+
+2000-01-02
+	8:00 - 9:00 Meeting #bar
+	1h
+`)._SetFile("/new.klg", `
+2000-01-02
+	8:00-9:30 Meeting #bar
+	1h
+
+2000-01-03
+	3h #foo
+`)._Run((&Diff{Old: "/old.klg", New: "/new.klg"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+- 2000-01-01  -2h
+    - 2h #foo
+~ 2000-01-02  +30m
+    - 8:00 - 9:00 Meeting #bar
+    + 8:00 - 9:30 Meeting #bar
++ 2000-01-03  +3h
+    + 3h #foo
+
+Tags:
+#bar +30m
+#foo +1h 
+
+Total: +1h30m
+`, state.printBuffer)
+}
+
+func TestDiffIgnoresFormatting(t *testing.T) {
+	state, err := NewTestingContext()._SetFile("/old.klg", `
+2000-01-01
+    8:00 - 9:00
+`)._SetFile("/new.klg", `
+2000-01-01
+	8:00-9:00
+`)._Run((&Diff{Old: "/old.klg", New: "/new.klg"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\nNo differences\n", state.printBuffer)
+}
+
+func TestDiffComparesGitRevisionWithCurrentFile(t *testing.T) {
+	state, err := NewTestingContext()._SetFile("HEAD~1:/times.klg", `
+2000-01-01
+	2h
+`)._SetFile("/times.klg", `
+2000-01-01 (8h!)
+	2h
+`)._Run((&Diff{Old: "HEAD~1:/times.klg"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+~ 2000-01-01  0m
+
+Total: 0m
+`, state.printBuffer)
+}
+
+func TestDiffRequiresTwoVersions(t *testing.T) {
+	_, err := NewTestingContext()._SetFile("/times.klg", "").
+		_Run((&Diff{Old: "/times.klg"}).Run)
+	require.Error(t, err)
+}
+
+func TestSplitRevision(t *testing.T) {
+	for _, x := range []struct {
+		arg      app.FileOrBookmarkName
+		revision string
+		path     app.FileOrBookmarkName
+	}{
+		{"times.klg", "", "times.klg"},
+		{"HEAD:times.klg", "HEAD", "times.klg"},
+		{"main~2:@work", "main~2", "@work"},
+		{`C:\times.klg`, "", `C:\times.klg`},
+	} {
+		revision, path := splitRevision(x.arg)
+		assert.Equal(t, x.revision, revision)
+		assert.Equal(t, x.path, path)
+	}
+}
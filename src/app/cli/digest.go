@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/jotaen/klog/lib/jotaen/terminalformat"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/service"
+	"strings"
+)
+
+type Digest struct {
+	GroupBy string `name:"group" short:"g" help:"Group entries by: day, week, tag" enum:"DAY,day,d,WEEK,week,w,TAG,tag,t," default:"day"`
+	Format  string `name:"format" help:"Output format: text, markdown" enum:"text,markdown,md" default:"text"`
+	lib.FilterArgs
+	lib.WarnArgs
+	lib.NoStyleArgs
+	lib.InputFilesArgs
+}
+
+func (opt *Digest) Help() string {
+	return `Prints what you worked on, e.g. for standups or timesheets.
+
+The entries are grouped by day, week or tag, and entries with identical summaries are merged into one line, with their durations summed up.
+With --format=markdown, the digest can be pasted into tickets or documents.
+
+Examples:
+    klog summary --yesterday
+    klog summary --since=2024-03-04 --group=tag --format=markdown`
+}
+
+// digestItem is the merged total of all entries with the same summary.
+type digestItem struct {
+	summary string
+	total   Duration
+}
+
+type digestGroup struct {
+	title string
+	total Duration
+	items []*digestItem
+}
+
+func (opt *Digest) Run(ctx app.Context) error {
+	isMarkdown := opt.Format == "markdown" || opt.Format == "md"
+	if isMarkdown {
+		opt.NoStyle = true
+	}
+	opt.NoStyleArgs.Apply(&ctx)
+	opt.FilterArgs.Apply(&ctx)
+	records, err := ctx.ReadInputs(opt.File...)
+	if err != nil {
+		return err
+	}
+	now := ctx.Now()
+	records = service.Sort(opt.ApplyFilter(now, records), true)
+	if len(records) == 0 {
+		return nil
+	}
+
+	var groups []digestGroup
+	category := "d"
+	if opt.GroupBy != "" {
+		category = strings.ToLower(opt.GroupBy[:1])
+	}
+	if category == "t" {
+		groups = digestByTag(records)
+	} else {
+		aggregator := newAggregator(category, nil)
+		recordGroups, dates := groupByDate(aggregator.DateHash, records)
+		for _, d := range dates {
+			title := d.ToString()
+			if category == "w" {
+				title = fmt.Sprintf("%d, Week %d", d.Year(), d.WeekNumber())
+			}
+			var entries []Entry
+			for _, r := range recordGroups[aggregator.DateHash(d)] {
+				entries = append(entries, r.Entries()...)
+			}
+			groups = append(groups, newDigestGroup(title, entries))
+		}
+	}
+
+	s := ctx.Serialiser()
+	for i, g := range groups {
+		if i > 0 {
+			ctx.Print("\n")
+		}
+		if isMarkdown {
+			ctx.Print("## " + g.title + " (" + g.total.ToString() + ")\n\n")
+			for _, item := range g.items {
+				ctx.Print("- " + item.summary + " (" + item.total.ToString() + ")\n")
+			}
+			continue
+		}
+		ctx.Print(g.title + "  " + s.Duration(g.total) + "\n")
+		table := terminalformat.NewTable(3, " ")
+		for _, item := range g.items {
+			table.CellL("   ").CellR(s.Duration(item.total)).CellL(s.Summary(Summary(item.summary)))
+		}
+		table.Collect(ctx.Print)
+	}
+	if !isMarkdown {
+		ctx.Print(opt.WarnArgs.ToString(now, records))
+	}
+	return nil
+}
+
+// digestByTag groups the entries by their tags, in alphabetical order. Entries
+// with multiple tags appear in multiple groups. Untagged entries come last.
+func digestByTag(records []Record) []digestGroup {
+	var groups []digestGroup
+	entriesByTag, _ := service.EntryTagLookup(records...)
+	for _, t := range sortTags(entriesByTag) {
+		groups = append(groups, newDigestGroup(t.ToString(), entriesByTag[t]))
+	}
+	var untagged []Entry
+	for _, r := range records {
+		if len(r.Summary().Tags()) > 0 {
+			continue
+		}
+		for _, e := range r.Entries() {
+			if len(e.Summary().Tags()) == 0 {
+				untagged = append(untagged, e)
+			}
+		}
+	}
+	if len(untagged) > 0 {
+		groups = append(groups, newDigestGroup("(untagged)", untagged))
+	}
+	return groups
+}
+
+// newDigestGroup merges the entries with identical summaries, in the order
+// of their first occurrence.
+func newDigestGroup(title string, entries []Entry) digestGroup {
+	group := digestGroup{title: title, total: NewDuration(0, 0)}
+	items := make(map[string]*digestItem)
+	for _, e := range entries {
+		summary := strings.Join(strings.Fields(e.Summary().ToString()), " ")
+		if summary == "" {
+			summary = "(no summary)"
+		}
+		item, ok := items[summary]
+		if !ok {
+			item = &digestItem{summary: summary, total: NewDuration(0, 0)}
+			items[summary] = item
+			group.items = append(group.items, item)
+		}
+		item.total = item.total.Plus(e.Duration())
+		group.total = group.total.Plus(e.Duration())
+	}
+	return group
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const digestRecords = `
+2000-01-03
+	1h Code review #work
+	8:00 - 9:00 Standup
+	30m Code review #work
+
+2000-01-04
+	2h Code review
+		#work
+	8:00 - 8:15
+
+2000-01-10
+#side
+	45m Blog post
+`
+
+func TestDigestGroupsByDay(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(digestRecords)._Run((&Digest{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2000-01-03  2h30m
+    1h30m Code review #work
+       1h Standup          
+
+2000-01-04  2h15m
+     2h Code review #work
+    15m (no summary)     
+
+2000-01-10  45m
+    45m Blog post
+`, state.printBuffer)
+}
+
+func TestDigestGroupsByWeek(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(digestRecords)._Run((&Digest{GroupBy: "week"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2000, Week 1  4h45m
+    3h30m Code review #work
+       1h Standup          
+      15m (no summary)     
+
+2000, Week 2  45m
+    45m Blog post
+`, state.printBuffer)
+}
+
+func TestDigestGroupsByTagAsMarkdown(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(digestRecords)._Run((&Digest{GroupBy: "tag", Format: "markdown"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+## #side (45m)
+
+- Blog post (45m)
+
+## #work (3h30m)
+
+- Code review #work (3h30m)
+
+## (untagged) (1h15m)
+
+- Standup (1h)
+- (no summary) (15m)
+`, state.printBuffer)
+}
+
+func TestDigestWithoutRecords(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords("")._Run((&Digest{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "", state.printBuffer)
+}
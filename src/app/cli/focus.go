@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	gotime "time"
+)
+
+type Focus struct {
+	Work         Duration `name:"work" short:"w" help:"Length of a focus interval (default: 25m)"`
+	Break        Duration `name:"break" short:"b" help:"Length of a break between focus intervals (default: 5m)"`
+	Rounds       int      `name:"rounds" short:"r" default:"1" help:"Number of focus intervals"`
+	RecordBreaks bool     `name:"record-breaks" help:"Record the breaks as separate entries"`
+	BreakTag     string   `name:"break-tag" default:"break" help:"Tag for the recorded breaks"`
+	Summary      string   `name:"summary" short:"s" help:"Summary text for the focus entries"`
+	NoPrompt     bool     `name:"no-prompt" help:"Don’t ask for a summary after a focus interval"`
+	lib.NoStyleArgs
+	lib.OutputFileArgs
+
+	// countdown waits for the duration to elapse, and returns false if
+	// it was interrupted. (Can be substituted for testing.)
+	countdown func(ctx app.Context, label string, d gotime.Duration) bool
+}
+
+func (opt *Focus) Help() string {
+	return `Runs a focus timer (Pomodoro technique) in the terminal, and tracks the focus intervals in the file.
+
+When a focus interval starts, an open-ended entry is appended to the record, e.g. 14:00-?.
+The entry is closed when the interval is over, or when you interrupt the timer via ^C.
+Afterwards, you are asked what you have worked on, and your answer is appended to the summary of the entry.
+
+With --rounds, several focus intervals are run, with a break in between.
+With --record-breaks, the breaks are recorded as separate entries, e.g. 14:25-14:30 #break.`
+}
+
+func (opt *Focus) Run(ctx app.Context) error {
+	opt.NoStyleArgs.Apply(&ctx)
+	if opt.File == lib.STDIO {
+		return errors.New("The focus timer cannot operate on stdin")
+	}
+	work := NewDuration(0, 25)
+	if opt.Work != nil {
+		work = opt.Work
+	}
+	pause := NewDuration(0, 5)
+	if opt.Break != nil {
+		pause = opt.Break
+	}
+	countdown := opt.countdown
+	if countdown == nil {
+		countdown = runCountdown
+	}
+	rounds := opt.Rounds
+	if rounds < 1 {
+		rounds = 1
+	}
+	for i := 1; i <= rounds; i++ {
+		date := NewDateFromTime(ctx.Now())
+		start := &Start{Summary: opt.Summary, NoStyleArgs: opt.NoStyleArgs, OutputFileArgs: opt.OutputFileArgs}
+		start.Date = date
+		if err := start.Run(ctx); err != nil {
+			return err
+		}
+		isCompleted := countdown(ctx, fmt.Sprintf("Focus %d/%d", i, rounds), toGoDuration(work))
+		summary := ""
+		if !opt.NoPrompt {
+			ctx.Print("What did you work on? ")
+			summary, _ = ctx.ReadLine()
+		}
+		stop := &Stop{Summary: strings.TrimSpace(summary), NoStyleArgs: opt.NoStyleArgs, OutputFileArgs: opt.OutputFileArgs}
+		stop.Date = date
+		stop.Time = timeOnDate(date, ctx.Now())
+		if err := stop.Run(ctx); err != nil {
+			return err
+		}
+		if !isCompleted || i == rounds {
+			return nil
+		}
+
+		breakStart := timeOnDate(date, ctx.Now())
+		isCompleted = countdown(ctx, "Break", toGoDuration(pause))
+		if opt.RecordBreaks {
+			entry := breakStart.ToString() + " - " + timeOnDate(date, ctx.Now()).ToString()
+			if opt.BreakTag != "" {
+				entry += " " + NewTag(opt.BreakTag).ToString()
+			}
+			track := &Track{Entry: entry, NoStyleArgs: opt.NoStyleArgs, OutputFileArgs: opt.OutputFileArgs}
+			track.Date = date
+			if err := track.Run(ctx); err != nil {
+				return err
+			}
+		}
+		if !isCompleted {
+			return nil
+		}
+	}
+	return nil
+}
+
+func toGoDuration(d Duration) gotime.Duration {
+	return gotime.Duration(d.InMinutes()) * gotime.Minute
+}
+
+// timeOnDate returns the time relative to the given date, i.e. it is shifted
+// to the next day if the date is yesterday.
+func timeOnDate(date Date, now gotime.Time) Time {
+	time := NewTimeFromTime(now)
+	if NewDateFromTime(now).PlusDays(-1).IsEqualTo(date) {
+		shifted, err := time.Add(NewDuration(24, 0))
+		if err == nil {
+			return shifted
+		}
+	}
+	return time
+}
+
+// runCountdown displays the remaining time, in the same manner as `withRepeat`.
+func runCountdown(ctx app.Context, label string, d gotime.Duration) bool {
+	// Handle ^C gracefully, so that the entry can be closed afterwards
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(c)
+
+	end := ctx.Now().Add(d)
+	ctx.Print("\033[2J") // Initial screen clearing
+	ticker := gotime.NewTicker(1 * gotime.Second)
+	defer ticker.Stop()
+	for {
+		remaining := end.Sub(ctx.Now()).Round(gotime.Second)
+		ctx.Print("\033[H\033[J") // Cursor reset
+		if remaining <= 0 {
+			ctx.Print(label + " is over.\a\n")
+			return true
+		}
+		ctx.Print(fmt.Sprintf("%s: %02d:%02d remaining\n\nPress ^C to stop\n",
+			label, int(remaining.Minutes()), int(remaining.Seconds())%60))
+		select {
+		case <-ticker.C:
+		case <-c:
+			ctx.Print("\n")
+			return false
+		}
+	}
+}
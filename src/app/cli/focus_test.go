@@ -0,0 +1,117 @@
+package cli
+
+import (
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	gotime "time"
+)
+
+// fakeCountdown lets the time elapse on the testing context. The countdowns
+// with the given indices are interrupted after half of their duration.
+func fakeCountdown(labels *[]string, interruptAt ...int) func(app.Context, string, gotime.Duration) bool {
+	return func(ctx app.Context, label string, d gotime.Duration) bool {
+		*labels = append(*labels, label)
+		tctx := ctx.(*TestingContext)
+		for _, i := range interruptAt {
+			if i == len(*labels)-1 {
+				tctx.now = tctx.now.Add(d / 2)
+				return false
+			}
+		}
+		tctx.now = tctx.now.Add(d)
+		return true
+	}
+}
+
+func TestFocus(t *testing.T) {
+	var labels []string
+	state, err := NewTestingContext().
+		_SetFile("/times.klg", "2020-01-01\n    1h\n").
+		_SetNow(2020, 1, 1, 10, 00).
+		_SetInputLines("Wrote #tests").
+		_Run((&Focus{
+			Summary:        "#klog",
+			OutputFileArgs: lib.OutputFileArgs{File: "/times.klg"},
+			countdown:      fakeCountdown(&labels),
+		}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Focus 1/1"}, labels)
+	assert.Equal(t, "2020-01-01\n    1h\n    10:00 - 10:25 #klog Wrote #tests\n", state.writtenFiles["/times.klg"])
+	assert.Contains(t, state.printBuffer, "What did you work on? ")
+}
+
+func TestFocusWithSeveralRoundsAndBreaks(t *testing.T) {
+	var labels []string
+	state, err := NewTestingContext().
+		_SetFile("/times.klg", "").
+		_SetNow(2020, 1, 1, 10, 00).
+		_Run((&Focus{
+			Work:           NewDuration(0, 50),
+			Break:          NewDuration(0, 10),
+			Rounds:         3,
+			RecordBreaks:   true,
+			BreakTag:       "pause",
+			NoPrompt:       true,
+			OutputFileArgs: lib.OutputFileArgs{File: "/times.klg"},
+			countdown:      fakeCountdown(&labels),
+		}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Focus 1/3", "Break", "Focus 2/3", "Break", "Focus 3/3"}, labels)
+	assert.Equal(t, `2020-01-01
+    10:00 - 10:50
+    10:50 - 11:00 #pause
+    11:00 - 11:50
+    11:50 - 12:00 #pause
+    12:00 - 12:50
+`, state.writtenFiles["/times.klg"])
+	assert.NotContains(t, state.printBuffer, "What did you work on?")
+}
+
+func TestFocusClosesRangeWhenInterrupted(t *testing.T) {
+	var labels []string
+	state, err := NewTestingContext().
+		_SetFile("/times.klg", "").
+		_SetNow(2020, 1, 1, 10, 00).
+		_Run((&Focus{
+			Rounds:         2,
+			NoPrompt:       true,
+			OutputFileArgs: lib.OutputFileArgs{File: "/times.klg"},
+			countdown:      fakeCountdown(&labels, 0),
+		}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Focus 1/2"}, labels)
+	assert.Equal(t, "2020-01-01\n    10:00 - 10:12\n", state.writtenFiles["/times.klg"])
+}
+
+func TestFocusRecordsInterruptedBreak(t *testing.T) {
+	var labels []string
+	state, err := NewTestingContext().
+		_SetFile("/times.klg", "").
+		_SetNow(2020, 1, 1, 23, 30).
+		_Run((&Focus{
+			Break:          NewDuration(0, 20),
+			Rounds:         2,
+			RecordBreaks:   true,
+			BreakTag:       "break",
+			NoPrompt:       true,
+			OutputFileArgs: lib.OutputFileArgs{File: "/times.klg"},
+			countdown:      fakeCountdown(&labels, 1),
+		}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Focus 1/2", "Break"}, labels)
+	assert.Equal(t, "2020-01-01\n    23:30 - 23:55\n    23:55 - 0:05> #break\n", state.writtenFiles["/times.klg"])
+}
+
+func TestFocusCannotOperateOnStdin(t *testing.T) {
+	var labels []string
+	_, err := NewTestingContext()._SetStdin("")._Run((&Focus{
+		OutputFileArgs: lib.OutputFileArgs{File: lib.STDIO},
+		countdown:      fakeCountdown(&labels),
+	}).Run)
+	require.Error(t, err)
+	assert.Len(t, labels, 0)
+}
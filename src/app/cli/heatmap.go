@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/lib/jotaen/terminalformat"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/service"
+	"strings"
+)
+
+type Heatmap struct {
+	lib.FilterArgs
+	lib.NoStyleArgs
+	lib.InputFilesArgs
+}
+
+func (opt *Heatmap) Help() string {
+	return `Renders a calendar heatmap of the daily total times, where every column is one week.
+
+Days without any record are marked with a dot.
+The shades are relative to the highest daily total in the displayed range.
+The filter flags can be used to limit the range, or to only include particular tags.`
+}
+
+type heatmapLevel struct {
+	char  string
+	plain string
+	color string
+}
+
+var heatmapNoRecord = heatmapLevel{"·", ".", "238"}
+
+var heatmapLevels = []heatmapLevel{
+	{"■", "_", "240"},
+	{"■", "-", "022"},
+	{"■", "+", "028"},
+	{"■", "*", "034"},
+	{"■", "#", "046"},
+}
+
+func (opt *Heatmap) Run(ctx app.Context) error {
+	opt.NoStyleArgs.Apply(&ctx)
+	opt.FilterArgs.Apply(&ctx)
+	records, err := ctx.ReadInputs(opt.File...)
+	if err != nil {
+		return err
+	}
+	records = opt.ApplyFilter(ctx.Now(), records)
+	if len(records) == 0 {
+		return nil
+	}
+	records = service.Sort(records, true)
+	totals := make(map[service.DayHash]Duration)
+	maxTotal := NewDuration(0, 0)
+	for _, r := range records {
+		hash := service.NewDayHash(r.Date())
+		if totals[hash] == nil {
+			totals[hash] = NewDuration(0, 0)
+		}
+		totals[hash] = totals[hash].Plus(service.Total(r))
+		if totals[hash].InMinutes() > maxTotal.InMinutes() {
+			maxTotal = totals[hash]
+		}
+	}
+	first := records[0].Date()
+	last := records[len(records)-1].Date()
+	start := first.PlusDays(-(first.Weekday() - 1))
+	numberOfWeeks := 0
+	for d := start; !d.IsAfterOrEqual(last.PlusDays(1)); d = d.PlusDays(7) {
+		numberOfWeeks++
+	}
+
+	format := func(l heatmapLevel) string {
+		if opt.IsStyled() {
+			return terminalformat.Style{Color: l.color}.Format(l.char)
+		}
+		return l.plain
+	}
+
+	// Month labels
+	labels := []rune(strings.Repeat(" ", 4+numberOfWeeks*2))
+	nextFreePosition := 0
+	for i, d := 0, first; last.IsAfterOrEqual(d); i, d = i+1, d.PlusDays(1) {
+		if i > 0 && d.Day() != 1 {
+			continue
+		}
+		week := (first.Weekday() - 1 + i) / 7
+		position := 4 + week*2
+		if position < nextFreePosition {
+			// The label would overlap with the previous one (e.g. if the first
+			// month only spans a few days), so it’s shifted to the right.
+			position = nextFreePosition
+		}
+		label := []rune(lib.PrettyMonth(d.Month())[:3])
+		if position+len(label) > len(labels) {
+			labels = append(labels, []rune(strings.Repeat(" ", position+len(label)-len(labels)))...)
+		}
+		copy(labels[position:], label)
+		nextFreePosition = position + len(label) + 1
+	}
+	ctx.Print(strings.TrimRight(string(labels), " ") + "\n")
+
+	// Weekdays
+	for weekday := 1; weekday <= 7; weekday++ {
+		line := lib.PrettyDay(weekday)[:3] + " "
+		for w := 0; w < numberOfWeeks; w++ {
+			d := start.PlusDays(w*7 + weekday - 1)
+			if !d.IsAfterOrEqual(first) || !last.IsAfterOrEqual(d) {
+				line += "  "
+				continue
+			}
+			total := totals[service.NewDayHash(d)]
+			if total == nil {
+				line += format(heatmapNoRecord) + " "
+				continue
+			}
+			line += format(heatmapLevels[heatmapLevelOf(total, maxTotal)]) + " "
+		}
+		ctx.Print(strings.TrimRight(line, " ") + "\n")
+	}
+
+	// Legend
+	lessOrEqual := "≤"
+	if !opt.IsStyled() {
+		lessOrEqual = "<="
+	}
+	legend := "\n" + format(heatmapNoRecord) + " no record"
+	for i, l := range heatmapLevels {
+		legend += "   " + format(l) + " "
+		if i == 0 {
+			legend += ctx.Serialiser().Duration(NewDuration(0, 0))
+			continue
+		}
+		limit := NewDuration(0, maxTotal.InMinutes()*i/(len(heatmapLevels)-1))
+		legend += lessOrEqual + ctx.Serialiser().Duration(limit)
+	}
+	ctx.Print(legend + "\n")
+	return nil
+}
+
+// heatmapLevelOf returns the shade of a daily total, relative to the highest
+// daily total. Level 0 is reserved for days without any time.
+func heatmapLevelOf(total Duration, maxTotal Duration) int {
+	if total.InMinutes() <= 0 || maxTotal.InMinutes() <= 0 {
+		return 0
+	}
+	steps := len(heatmapLevels) - 1
+	return (total.InMinutes()*steps + maxTotal.InMinutes() - 1) / maxTotal.InMinutes()
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestHeatmapOfEmptyInput(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(``)._Run((&Heatmap{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "", state.printBuffer)
+}
+
+func TestPrintHeatmap(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2021-01-27
+	8h #work
+
+2021-01-28
+	2h #work
+	2h #sports
+
+2021-01-29
+	1h #work
+
+2021-02-01
+	7h #work
+
+2021-02-02
+
+2021-02-09
+	30m #work
+	4h #sports
+`)._Run((&Heatmap{NoStyleArgs: lib.NoStyleArgs{NoStyle: true}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+    Jan Feb
+Mon   # .
+Tue   _ *
+Wed # .
+Thu + .
+Fri - .
+Sat . .
+Sun . .
+
+. no record   _ 0m   - <=2h   + <=4h   * <=6h   # <=8h
+`, state.printBuffer)
+}
@@ -1,30 +1,53 @@
 package cli
 
 type Cli struct {
+	// Global flags
+	Errors string `name:"errors" help:"Output format of parser errors: text, json, gcc" enum:"text,json,gcc" default:"text"`
+
 	// Evaluate
-	Print  Print  `cmd group:"Evaluate" help:"Pretty-prints records"`
-	Total  Total  `cmd group:"Evaluate" help:"Evaluates the total time"`
-	Report Report `cmd group:"Evaluate" help:"Prints a calendar report summarising all days"`
-	Tags   Tags   `cmd group:"Evaluate" help:"Prints total times aggregated by tags"`
-	Today  Today  `cmd group:"Evaluate" help:"Evaluates the current day"`
+	Print    Print    `cmd group:"Evaluate" help:"Pretty-prints records"`
+	Total    Total    `cmd group:"Evaluate" help:"Evaluates the total time"`
+	Report   Report   `cmd group:"Evaluate" help:"Prints a calendar report summarising all days"`
+	Tags     Tags     `cmd group:"Evaluate" help:"Prints total times aggregated by tags"`
+	Today    Today    `cmd group:"Evaluate" help:"Evaluates the current day"`
+	Balance  Balance  `cmd group:"Evaluate" help:"Shows the accumulated flex-time balance"`
+	Leave    Leave    `cmd group:"Evaluate" help:"Lists days off and compares them to quotas"`
+	Heatmap  Heatmap  `cmd group:"Evaluate" help:"Renders a calendar heatmap of the daily totals"`
+	Stats    Stats    `cmd group:"Evaluate" help:"Computes statistics, such as averages and streaks"`
+	Timeline Timeline `cmd group:"Evaluate" help:"Visualises the ranges of records along the hours of the day"`
+	Diff     Diff     `cmd group:"Evaluate" help:"Compares two versions of records, e.g. from git history"`
+	Digest   Digest   `cmd name:"summary" group:"Evaluate" help:"Prints a digest of what you worked on, for standups or timesheets"`
 
 	// Manipulate
-	Track  Track  `cmd group:"Manipulate" help:"Adds a new entry to a record"`
-	Start  Start  `cmd group:"Manipulate" aliases:"in" help:"Starts open time range"`
-	Stop   Stop   `cmd group:"Manipulate" aliases:"out" help:"Closes open time range"`
-	Create Create `cmd group:"Manipulate" help:"Creates a new record"`
+	Track   Track   `cmd group:"Manipulate" help:"Adds a new entry to a record"`
+	Start   Start   `cmd group:"Manipulate" aliases:"in" help:"Starts open time range"`
+	Stop    Stop    `cmd group:"Manipulate" aliases:"out" help:"Closes open time range"`
+	Focus   Focus   `cmd group:"Manipulate" help:"Runs a focus timer and tracks the intervals"`
+	Create  Create  `cmd group:"Manipulate" help:"Creates a new record"`
+	Amend   Amend   `cmd group:"Manipulate" aliases:"edit-entry" help:"Modifies or removes an existing entry"`
+	Delete  Delete  `cmd group:"Manipulate" help:"Removes records from a file"`
+	Move    Move    `cmd group:"Manipulate" help:"Moves records from one file to another"`
+	Archive Archive `cmd group:"Manipulate" help:"Moves old records into archive files"`
 
 	// Bookmarks
 	Bookmarks Bookmarks `cmd group:"Bookmarks" help:"Named aliases for often-used files"`
 	Bookmark  Bookmarks `cmd group:"Bookmarks" hidden help:"Alias"`
 	Bm        Bookmarks `cmd group:"Bookmarks" hidden help:"Alias"`
 
+	// Templates
+	Templates Templates `cmd group:"Templates" help:"Reusable skeletons for new records"`
+	Template  Templates `cmd group:"Templates" hidden help:"Alias"`
+
 	// Misc
 	Edit    Edit    `cmd group:"Misc" help:"Opens a file or bookmark in your editor"`
 	Json    Json    `cmd group:"Misc" help:"Converts records to JSON"`
 	Widget  Widget  `cmd group:"Misc" help:"Starts menu bar widget (MacOS only)"`
+	Lsp     Lsp     `cmd group:"Misc" help:"Starts a language server for editor integration"`
 	Version Version `cmd group:"Misc" help:"Prints version info and check for updates"`
 
+	Completion Completion `cmd group:"Misc" help:"Prints a script for tab completion in the shell"`
+	Complete   Complete   `cmd name:"__complete" hidden help:"Suggests values for tab completion"`
+
 	// Default command for displaying info text (hidden)
 	Info Info `cmd default:"withargs" hidden:"1"`
 }
@@ -1,8 +1,10 @@
 package cli
 
 import (
+	"errors"
 	"github.com/jotaen/klog/src/app"
 	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/parser"
 	"github.com/jotaen/klog/src/parser/json"
 	"github.com/jotaen/klog/src/parser/parsing"
 )
@@ -11,7 +13,10 @@ type Json struct {
 	lib.FilterArgs
 	lib.SortArgs
 	lib.InputFilesArgs
-	Pretty bool `name:"pretty" help:"Pretty-print output"`
+	Pretty   bool                   `name:"pretty" help:"Pretty-print output"`
+	Comments bool                   `name:"comments" help:"Include the comments of the records"`
+	From     string                 `name:"from" placeholder:"FILE" help:"Convert JSON (in the output structure of this command) back to klog. Use - to read from stdin"`
+	Into     app.FileOrBookmarkName `name:"into" placeholder:"FILE" help:"In conjunction with --from: insert the records into this file instead of printing them"`
 }
 
 func (opt *Json) Help() string {
@@ -22,21 +27,104 @@ If the file is valid, "records" is an array containing a JSON object for each re
 If the file has syntax errors, "records" is null and "errors" contains an array of error objects.
 
 The structure of the objects is always uniform, so you can explore it by running the command with the --pretty flag.
+
+With --from, the conversion works the other way round: the records are read from JSON and printed in the klog file format, or inserted into the file specified by --into.
+Computed values such as "total" or "tags" are ignored when reading JSON. If the JSON is invalid, the output contains the errors in the same structure as described above.
 `
 }
 
 func (opt *Json) Run(ctx app.Context) error {
+	if opt.From != "" {
+		return opt.runFromJson(ctx)
+	}
+	if opt.Into != "" {
+		return errors.New("The --into flag can only be used in conjunction with --from")
+	}
+	opt.FilterArgs.Apply(&ctx)
 	records, err := ctx.ReadInputs(opt.File...)
 	if err != nil {
 		parserErrs, isParserErr := err.(parsing.Errors)
 		if isParserErr {
-			ctx.Print(json.ToJson(nil, parserErrs, opt.Pretty) + "\n")
+			ctx.Print(json.ToJson(nil, parserErrs, opt.Pretty, false) + "\n")
 			return nil
 		}
 		return err
 	}
 	records = opt.ApplyFilter(ctx.Now(), records)
 	records = opt.ApplySort(records)
-	ctx.Print(json.ToJson(records, nil, opt.Pretty) + "\n")
+	ctx.Print(json.ToJson(records, nil, opt.Pretty, opt.Comments) + "\n")
+	return nil
+}
+
+func (opt *Json) runFromJson(ctx app.Context) error {
+	if len(opt.File) > 0 {
+		return errors.New("Input files cannot be used in conjunction with --from")
+	}
+	if opt.From == "-" && opt.Into == lib.STDIO {
+		// Stdin can only be read once, so it cannot provide both inputs.
+		return errors.New("--from and --into cannot both read from stdin")
+	}
+	input, err := func() (string, app.Error) {
+		if opt.From == "-" {
+			return app.ReadStdin()
+		}
+		file, err := app.NewFile(opt.From)
+		if err != nil {
+			return "", err
+		}
+		return app.ReadFile(file)
+	}()
+	if err != nil {
+		return err
+	}
+	records, errs := json.FromJson(input)
+	if errs != nil {
+		ctx.Print(json.ToJson(nil, errs, opt.Pretty, false) + "\n")
+		return nil
+	}
+	records = opt.ApplyFilter(ctx.Now(), records)
+	records = opt.ApplySort(records)
+	if opt.Into == "" {
+		ctx.Print(parser.PlainSerialiser.SerialiseRecordsWithComments(records...))
+		return nil
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	var pr *parser.ParseResult
+	var target app.File
+	var rErr error
+	if opt.Into == lib.STDIO {
+		pr, rErr = ctx.ReadStdinInput()
+	} else {
+		pr, target, rErr = ctx.ReadFileInput(opt.Into)
+	}
+	if rErr != nil {
+		return rErr
+	}
+
+	// All records are inserted first, so that the file is only written once,
+	// and so that it is left untouched if one of the records doesn’t fit in.
+	var result *parser.ReconcileResult
+	for _, r := range records {
+		texts, tErr := parser.SerialiseRecordToTexts(r)
+		if tErr != nil {
+			return tErr
+		}
+		result, tErr = parser.NewBlockReconciler(pr, r.Date()).InsertBlock(texts)
+		if tErr != nil {
+			return tErr
+		}
+		pr, _ = parser.Parse(result.NewText)
+	}
+	if target == nil {
+		ctx.Print(result.NewText)
+		return nil
+	}
+	err = ctx.WriteFile(target, result.NewText)
+	if err != nil {
+		return err
+	}
+	ctx.Print("\n" + ctx.Serialiser().SerialiseRecords(records...) + "\n")
 	return nil
 }
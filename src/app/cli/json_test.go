@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeJsonFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "records.json")
+	require.Nil(t, os.WriteFile(path, []byte(contents), 0644))
+	return path
+}
+
+func TestConvertFromJson(t *testing.T) {
+	path := writeJsonFile(t, `{"records":[
+	{"date":"2020-01-02","summary":"Foo","entries":[{"type":"range","start":"8:00","end":"9:30","summary":"#bar"}]},
+	{"date":"2020-01-01","should_total":"8h!","entries":[]}
+]}`)
+	state, err := NewTestingContext()._Run((&Json{From: path, SortArgs: lib.SortArgs{Sort: "asc"}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2020-01-01 (8h!)
+
+2020-01-02
+Foo
+    8:00 - 9:30 #bar
+`, state.printBuffer)
+}
+
+func TestConvertFromJsonIntoFile(t *testing.T) {
+	path := writeJsonFile(t, `{"records":[{"date":"2020-01-02","entries":[{"type":"duration","total":"1h"}]}]}`)
+	state, err := NewTestingContext()._SetFile("/tmp/times.klg", `
+2020-01-01
+    2h
+
+2020-01-03
+    3h
+`)._Run((&Json{From: path, Into: "/tmp/times.klg"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2020-01-01
+    2h
+
+2020-01-02
+    1h
+
+2020-01-03
+    3h
+`, state.writtenFiles["/tmp/times.klg"])
+}
+
+func TestConvertFromJsonIntoFileWritesAllRecordsAtOnce(t *testing.T) {
+	path := writeJsonFile(t, `{"records":[
+		{"date":"2020-01-04","entries":[{"type":"duration","total":"4h"}]},
+		{"date":"2020-01-02","entries":[{"type":"duration","total":"1h"}]}
+	]}`)
+	state, err := NewTestingContext()._SetFile("/tmp/times.klg", `
+2020-01-01
+    2h
+
+2020-01-03
+    3h
+`)._Run((&Json{From: path, Into: "/tmp/times.klg"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, 1, state.numberOfWrites)
+	assert.Equal(t, `
+2020-01-01
+    2h
+
+2020-01-02
+    1h
+
+2020-01-03
+    3h
+
+2020-01-04
+    4h
+`, state.writtenFiles["/tmp/times.klg"])
+}
+
+func TestConvertFromJsonPrintsErrors(t *testing.T) {
+	path := writeJsonFile(t, `{"records":[{"date":"2020-01-32"}]}`)
+	state, err := NewTestingContext()._Run((&Json{From: path}).Run)
+	require.Nil(t, err)
+	assert.Contains(t, state.printBuffer, `"errors":[{"line":1,"column":13,"length":1,"title":"Invalid date"`)
+}
+
+func TestConvertFromJsonRejectsStdinForBothInputs(t *testing.T) {
+	_, err := NewTestingContext()._SetStdin("2020-01-01\n")._Run((&Json{From: "-", Into: "-"}).Run)
+	require.Error(t, err)
+}
+
+func TestConvertFromJsonRejectsInputFiles(t *testing.T) {
+	_, err := NewTestingContext()._Run((&Json{From: "foo.json", InputFilesArgs: lib.InputFilesArgs{File: []app.FileOrBookmarkName{"foo.klg"}}}).Run)
+	require.Error(t, err)
+}
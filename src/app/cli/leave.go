@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/jotaen/klog/lib/jotaen/terminalformat"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/app/cli/report"
+	"github.com/jotaen/klog/src/service"
+	"sort"
+)
+
+type Leave struct {
+	Year int `name:"year" help:"The year to evaluate (default: current year)"`
+	lib.NoStyleArgs
+	lib.InputFilesArgs
+}
+
+func (opt *Leave) Help() string {
+	return `Lists all days off (vacation, sick leave, public holidays) of a year, and compares them to the yearly quotas.
+
+A record is a day off if its summary contains one of the tags #vacation, #sick or #holiday. For example:
+    2021-08-02
+    #vacation
+
+Alternatively, days off can be configured in the "schedule" section of ~/.klog/config.json, along with the quotas:
+    {
+      "schedule": {
+        "holidays": ["2021-12-24", "2021-12-25"],
+        "calendars": ["/path/to/holidays.ics"],
+        "leave": [{"type": "vacation", "from": "2021-08-02", "until": "2021-08-13"}],
+        "quotas": {"vacation": 30}
+      },
+      "bookmarks": {
+        "work": {"calendars": ["/path/to/company-holidays.json"]}
+      }
+    }
+
+Public holidays can also be read from calendar files, either in iCalendar format (.ics) or as JSON list (e.g. ["2021-12-24"] or [{"date": "2021-12-24", "name": "Christmas Eve"}]).
+Calendars in the "bookmarks" section only apply when reading the respective bookmark.
+
+Days off from the config file are only counted if they are working days according to the schedule (or Monday to Friday, if there are no working hours configured).
+Public holidays have no should-total. The should-total of other days off is credited, so no time is owed on that day.`
+}
+
+var dayTypeLabels = map[service.DayType]string{
+	service.Vacation:      "Vacation",
+	service.SickLeave:     "Sick leave",
+	service.PublicHoliday: "Holiday",
+}
+
+func (opt *Leave) Run(ctx app.Context) error {
+	opt.NoStyleArgs.Apply(&ctx)
+	year := opt.Year
+	if year == 0 {
+		year = ctx.Now().Year()
+	}
+	ctx.IncludeArchives(func(y int) bool { return y == year })
+	records, err := ctx.ReadInputs(opt.File...)
+	if err != nil {
+		return err
+	}
+	config, err := ctx.ReadConfig(opt.File...)
+	if err != nil {
+		return err
+	}
+	schedule := config.Schedule
+	from, _ := NewDate(year, 1, 1)
+	until, _ := NewDate(year, 12, 31)
+	records = service.Filter(records, service.FilterQry{AfterOrEqual: from, BeforeOrEqual: until})
+	records = service.Sort(records, true)
+
+	// Collect all days off, where records take precedence over the schedule.
+	type dayOff struct {
+		date    Date
+		dayType service.DayType
+	}
+	var days []dayOff
+	recorded := make(map[service.DayHash]bool)
+	for _, r := range records {
+		h := service.NewDayHash(r.Date())
+		if recorded[h] {
+			continue
+		}
+		recorded[h] = true
+		if t := service.DayTypeOf(schedule, r); t != service.RegularDay {
+			days = append(days, dayOff{r.Date(), t})
+		}
+	}
+	for d := from; until.IsAfterOrEqual(d); d = d.PlusDays(1) {
+		if recorded[service.NewDayHash(d)] || !isWorkingDay(schedule, d) {
+			continue
+		}
+		if t := schedule.DayTypeAt(d); t != service.RegularDay {
+			days = append(days, dayOff{d, t})
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[j].date.IsAfterOrEqual(days[i].date)
+	})
+
+	// List of days
+	aggregator := report.NewDayAggregator()
+	list := terminalformat.NewTable(aggregator.NumberOfPrefixColumns()+1, " ")
+	taken := make(map[service.DayType]int)
+	for _, d := range days {
+		aggregator.OnRowPrefix(list, d.date)
+		list.CellL(" " + dayTypeLabels[d.dayType])
+		taken[d.dayType]++
+	}
+	list.Collect(ctx.Print)
+	if len(days) > 0 {
+		ctx.Print("\n")
+	}
+
+	// Quotas
+	summary := terminalformat.NewTable(4, " ")
+	summary.CellL(fmt.Sprint(year)).CellR("Taken").CellR("Quota").CellR(" Left")
+	for _, t := range service.DayTypes {
+		summary.CellL(dayTypeLabels[t]).CellR(fmt.Sprint(taken[t]))
+		quota, hasQuota := schedule.QuotaOf(t)
+		if !hasQuota {
+			summary.Skip(2)
+			continue
+		}
+		summary.CellR(fmt.Sprint(quota)).CellR(fmt.Sprint(quota - taken[t]))
+	}
+	summary.Collect(ctx.Print)
+	return nil
+}
+
+func isWorkingDay(schedule *service.Schedule, d Date) bool {
+	if schedule != nil && len(schedule.Periods) > 0 {
+		return schedule.WorkingHoursAt(d).InMinutes() > 0
+	}
+	return d.Weekday() <= 5
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLeaveListsDaysOffAndQuotas(t *testing.T) {
+	state, err := NewTestingContext()._SetConfig(app.Config{
+		Schedule: &service.Schedule{
+			Holidays: []service.Holiday{{Date: klog.Ɀ_Date_(2021, 12, 24)}, {Date: klog.Ɀ_Date_(2021, 12, 25)}},
+			Leave:    []service.Leave{{Type: service.Vacation, From: klog.Ɀ_Date_(2021, 8, 6), Until: klog.Ɀ_Date_(2021, 8, 9)}},
+			Quotas:   map[service.DayType]int{service.Vacation: 30},
+		},
+	})._SetRecords(`
+2021-03-01
+#sick
+	1h
+
+2020-08-10
+#vacation
+`)._Run((&Leave{Year: 2021}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2021 Mar Mon  1.  Sick leave
+     Aug Fri  6.  Vacation  
+         Mon  9.  Vacation  
+     Dec Fri 24.  Holiday   
+
+2021       Taken Quota  Left
+Vacation       2    30    28
+Sick leave     1            
+Holiday        1            
+`, state.printBuffer)
+}
@@ -50,6 +50,15 @@ type DiffArgs struct {
 	Diff bool `name:"diff" short:"d" help:"Show difference between actual and should-total time"`
 }
 
+// Config returns the user’s configuration (e.g. the work schedule), if the
+// diff is requested.
+func (args *DiffArgs) Config(ctx app.Context, fileArgs ...app.FileOrBookmarkName) (app.Config, error) {
+	if !args.Diff {
+		return app.Config{}, nil
+	}
+	return ctx.ReadConfig(fileArgs...)
+}
+
 type NowArgs struct {
 	Now bool `name:"now" short:"n" help:"Assume open ranges to be closed at this moment"`
 }
@@ -63,23 +72,66 @@ func (args *NowArgs) Total(reference gotime.Time, rs ...Record) Duration {
 }
 
 type FilterArgs struct {
-	Tags      []string `name:"tag" group:"Filter" help:"Only records (or particular entries) that match this tag"`
-	Date      []Date   `name:"date" group:"Filter" help:"Only records at this date"`
-	Today     bool     `name:"today" group:"Filter" help:"Only records at today’s date"`
-	Yesterday bool     `name:"yesterday" group:"Filter" help:"Only records at yesterday’s date"`
-	Since     Date     `name:"since" group:"Filter" help:"Only records since this date (inclusive)"`
-	Until     Date     `name:"until" group:"Filter" help:"Only records until this date (inclusive)"`
-	After     Date     `name:"after" group:"Filter" help:"Only records after this date (exclusive)"`
-	Before    Date     `name:"before" group:"Filter" help:"Only records before this date (exclusive)"`
-	Period    Period   `name:"period" group:"Filter" help:"Only records in this period (YYYY-MM or YYYY)"`
+	Tags      []string   `name:"tag" group:"Filter" help:"Only records (or particular entries) that match this tag"`
+	Date      []Date     `name:"date" group:"Filter" help:"Only records at this date"`
+	Today     bool       `name:"today" group:"Filter" help:"Only records at today’s date"`
+	Yesterday bool       `name:"yesterday" group:"Filter" help:"Only records at yesterday’s date"`
+	Since     Date       `name:"since" group:"Filter" help:"Only records since this date (inclusive)"`
+	Until     Date       `name:"until" group:"Filter" help:"Only records until this date (inclusive)"`
+	After     Date       `name:"after" group:"Filter" help:"Only records after this date (exclusive)"`
+	Before    Date       `name:"before" group:"Filter" help:"Only records before this date (exclusive)"`
+	Period    Period     `name:"period" group:"Filter" help:"Only records in this period (YYYY-MM or YYYY)"`
+	Property  []Property `name:"property" group:"Filter" help:"Only records with this property (KEY, KEY=VALUE or @LOCATION)"`
 }
 
 func (args *FilterArgs) ApplyFilter(now gotime.Time, rs []Record) []Record {
+	return service.Filter(rs, args.query(now))
+}
+
+// IsMatch checks whether a record satisfies the filter, without altering the record.
+func (args *FilterArgs) IsMatch(now gotime.Time, r Record) bool {
+	return service.Match(r, args.query(now))
+}
+
+func (args *FilterArgs) IsEmpty() bool {
+	return len(args.Tags) == 0 && len(args.Date) == 0 && !args.Today && !args.Yesterday &&
+		args.Since == nil && args.Until == nil && args.After == nil && args.Before == nil &&
+		args.Period.Since == nil && len(args.Property) == 0
+}
+
+// Apply makes the context include the archives of the input files, as far as
+// the filter reaches into the respective years.
+func (args *FilterArgs) Apply(ctx *app.Context) {
+	qry := args.query((*ctx).Now())
+	if qry.AfterOrEqual == nil && qry.BeforeOrEqual == nil && len(qry.Dates) == 0 {
+		return
+	}
+	(*ctx).IncludeArchives(func(year int) bool {
+		if qry.AfterOrEqual != nil && year < qry.AfterOrEqual.Year() {
+			return false
+		}
+		if qry.BeforeOrEqual != nil && year > qry.BeforeOrEqual.Year() {
+			return false
+		}
+		if len(qry.Dates) == 0 {
+			return true
+		}
+		for _, d := range qry.Dates {
+			if d.Year() == year {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (args *FilterArgs) query(now gotime.Time) service.FilterQry {
 	qry := service.FilterQry{
 		BeforeOrEqual: args.Until,
 		AfterOrEqual:  args.Since,
 		Tags:          args.Tags,
 		Dates:         args.Date,
+		Properties:    args.Property,
 	}
 	if args.Period.Since != nil {
 		qry.BeforeOrEqual = args.Period.Until
@@ -97,7 +149,7 @@ func (args *FilterArgs) ApplyFilter(now gotime.Time, rs []Record) []Record {
 	if args.Yesterday {
 		qry.Dates = append(qry.Dates, NewDateFromTime(now.AddDate(0, 0, -1)))
 	}
-	return service.Filter(rs, qry)
+	return qry
 }
 
 type WarnArgs struct {
@@ -117,11 +169,19 @@ type NoStyleArgs struct {
 }
 
 func (args *NoStyleArgs) Apply(ctx *app.Context) {
-	if args.NoStyle || os.Getenv("NO_COLOR") != "" {
+	if !args.IsStyled() {
 		(*ctx).SetSerialiser(&parser.PlainSerialiser)
 	}
 }
 
+func (args *NoStyleArgs) IsStyled() bool {
+	return !args.NoStyle && os.Getenv("NO_COLOR") == ""
+}
+
+type ChartArgs struct {
+	Chart bool `name:"chart" short:"c" help:"Show bar chart"`
+}
+
 type QuietArgs struct {
 	Quiet bool `name:"quiet" help:"Output parseable data without descriptive text"`
 }
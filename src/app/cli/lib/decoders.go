@@ -0,0 +1,125 @@
+package lib
+
+import (
+	"errors"
+	"github.com/alecthomas/kong"
+	. "github.com/jotaen/klog/src"
+	"reflect"
+	"strings"
+)
+
+// TypeMappers returns the options that make kong decode the klog data types
+// from the command line arguments.
+func TypeMappers() []kong.Option {
+	return []kong.Option{
+		func() kong.Option {
+			datePrototype, _ := NewDate(1, 1, 1)
+			return kong.TypeMapper(reflect.TypeOf(&datePrototype).Elem(), dateDecoder())
+		}(),
+		func() kong.Option {
+			timePrototype, _ := NewTime(0, 0)
+			return kong.TypeMapper(reflect.TypeOf(&timePrototype).Elem(), timeDecoder())
+		}(),
+		func() kong.Option {
+			durationPrototype := NewDuration(0, 0)
+			return kong.TypeMapper(reflect.TypeOf(&durationPrototype).Elem(), durationDecoder())
+		}(),
+		func() kong.Option {
+			property := Property{}
+			return kong.TypeMapper(reflect.TypeOf(&property).Elem(), propertyDecoder())
+		}(),
+		func() kong.Option {
+			period := Period{}
+			return kong.TypeMapper(reflect.TypeOf(&period).Elem(), periodDecoder())
+		}(),
+	}
+}
+
+func dateDecoder() kong.MapperFunc {
+	return func(ctx *kong.DecodeContext, target reflect.Value) error {
+		var value string
+		if err := ctx.Scan.PopValueInto("date", &value); err != nil {
+			return err
+		}
+		if value == "" {
+			return errors.New("Please provide a valid date")
+		}
+		d, err := NewDateFromString(value)
+		if err != nil {
+			return errors.New("`" + value + "` is not a valid date")
+		}
+		target.Set(reflect.ValueOf(d))
+		return nil
+	}
+}
+
+func timeDecoder() kong.MapperFunc {
+	return func(ctx *kong.DecodeContext, target reflect.Value) error {
+		var value string
+		if err := ctx.Scan.PopValueInto("time", &value); err != nil {
+			return err
+		}
+		if value == "" {
+			return errors.New("Please provide a valid time")
+		}
+		t, err := NewTimeFromString(value)
+		if err != nil {
+			return errors.New("`" + value + "` is not a valid time")
+		}
+		target.Set(reflect.ValueOf(t))
+		return nil
+	}
+}
+
+func durationDecoder() kong.MapperFunc {
+	return func(ctx *kong.DecodeContext, target reflect.Value) error {
+		var value string
+		if err := ctx.Scan.PopValueInto("duration", &value); err != nil {
+			return err
+		}
+		if value == "" {
+			return errors.New("Please provide a valid duration")
+		}
+		value = strings.TrimSuffix(value, "!")
+		d, err := NewDurationFromString(value)
+		if err != nil {
+			return errors.New("`" + value + "` is not a valid duration")
+		}
+		target.Set(reflect.ValueOf(d))
+		return nil
+	}
+}
+
+func propertyDecoder() kong.MapperFunc {
+	return func(ctx *kong.DecodeContext, target reflect.Value) error {
+		var value string
+		if err := ctx.Scan.PopValueInto("property", &value); err != nil {
+			return err
+		}
+		p, err := NewPropertyFromString(value)
+		if err != nil {
+			// A key on its own matches the property regardless of its value.
+			if !IsValidPropertyKey(value) {
+				return errors.New("`" + value + "` is not a valid property")
+			}
+			p = Property{Key: strings.ToLower(value)}
+		}
+		target.Set(reflect.ValueOf(p))
+		return nil
+	}
+}
+
+func periodDecoder() kong.MapperFunc {
+	return func(ctx *kong.DecodeContext, target reflect.Value) error {
+		var value string
+		if err := ctx.Scan.PopValueInto("period", &value); err != nil {
+			return err
+		}
+		p, err := NewPeriodFromString(value)
+		if err != nil {
+			return err
+		}
+		target.Set(reflect.ValueOf(p))
+		return nil
+	}
+}
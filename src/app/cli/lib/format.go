@@ -1,6 +1,8 @@
 package lib
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	. "github.com/jotaen/klog/lib/jotaen/terminalformat"
@@ -20,6 +22,9 @@ func NewCliSerialiser() *parser.Serialiser {
 		ShouldTotal: func(d Duration) string {
 			return Style{Color: "213"}.Format(d.ToString())
 		},
+		Property: func(p Property) string {
+			return Style{Color: "219"}.Format(p.ToString())
+		},
 		Summary: func(s Summary) string {
 			txt := s.ToString()
 			style := Style{Color: "249"}
@@ -52,6 +57,9 @@ func NewCliSerialiser() *parser.Serialiser {
 		Time: func(t Time) string {
 			return Style{Color: "027"}.Format(t.ToString())
 		},
+		Comment: func(text string) string {
+			return Style{Color: "242"}.Format(parsing.COMMENT_PREFIX + " " + text)
+		},
 	}
 }
 
@@ -92,6 +100,71 @@ func PrettifyError(err error, isDebug bool) error {
 	return errors.New("Error: " + err.Error())
 }
 
+const (
+	ERROR_FORMAT_TEXT = "text"
+	ERROR_FORMAT_JSON = "json"
+	ERROR_FORMAT_GCC  = "gcc"
+)
+
+type errorView struct {
+	File    *string `json:"file"`
+	Line    int     `json:"line"`
+	Column  int     `json:"column"`
+	Length  int     `json:"length"`
+	Code    string  `json:"code"`
+	Title   string  `json:"title"`
+	Details string  `json:"details"`
+}
+
+// FormatError renders parser errors in the given format, so that they can be
+// processed by editors or other tools. All other errors, as well as parser
+// errors in `text` format, are prettified for humans.
+func FormatError(err error, format string, isDebug bool) error {
+	errs, isParserError := err.(parsing.Errors)
+	if !isParserError || (format != ERROR_FORMAT_JSON && format != ERROR_FORMAT_GCC) {
+		return PrettifyError(err, isDebug)
+	}
+	var file *string
+	if fErr, isFileError := err.(app.FileErrors); isFileError && fErr.File != nil {
+		path := fErr.File.Path()
+		file = &path
+	}
+	if format == ERROR_FORMAT_GCC {
+		var lines []string
+		path := "<stdin>"
+		if file != nil {
+			path = *file
+		}
+		for _, e := range errs.Get() {
+			lines = append(lines, fmt.Sprintf(
+				"%s:%d:%d: %s: %s",
+				path, e.Context().LineNumber, e.Column(), e.Code(),
+				strings.ReplaceAll(e.Message(), "\n", " "),
+			))
+		}
+		return errors.New(strings.Join(lines, "\n"))
+	}
+	views := []errorView{}
+	for _, e := range errs.Get() {
+		views = append(views, errorView{
+			File:    file,
+			Line:    e.Context().LineNumber,
+			Column:  e.Column(),
+			Length:  e.Length(),
+			Code:    e.Code(),
+			Title:   e.Title(),
+			Details: e.Details(),
+		})
+	}
+	buffer := new(bytes.Buffer)
+	enc := json.NewEncoder(buffer)
+	enc.SetEscapeHTML(false)
+	if jErr := enc.Encode(map[string][]errorView{"errors": views}); jErr != nil {
+		panic(jErr) // This should never happen
+	}
+	return errors.New(strings.TrimRight(buffer.String(), "\n"))
+}
+
 func PrettifyWarnings(ws []service.Warning) string {
 	result := ""
 	for _, w := range ws {
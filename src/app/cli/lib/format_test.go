@@ -65,3 +65,47 @@ func TestFormatRegularError(t *testing.T) {
 	textWithNilErr := PrettifyError(errors.New("Some plain error"), true).Error()
 	assert.Equal(t, `Error: Some plain error`, textWithNilErr)
 }
+
+func sampleFileErrors(path string) error {
+	var file app.File
+	if path != "" {
+		file, _ = app.NewFile(path)
+	}
+	return app.NewFileErrors(file, parsing.NewErrors([]parsing.Error{
+		func() parsing.Error {
+			err := parsing.NewError(parsing.NewLineFromString("    Foo bar", 2), 4, 3)
+			return err.Set("ErrorFoo", "Some Title", "A description\nwith a newline.")
+		}(),
+		func() parsing.Error {
+			err := parsing.NewError(parsing.NewLineFromString("Some <malformed> text", 39), 0, 4)
+			return err.Set("ErrorBar", "Error", "Short explanation.")
+		}(),
+	}))
+}
+
+func TestFormatParserErrorAsGcc(t *testing.T) {
+	text := FormatError(sampleFileErrors("/tmp/times.klg"), ERROR_FORMAT_GCC, false).Error()
+	assert.Equal(t, `/tmp/times.klg:2:9: ErrorFoo: Some Title: A description with a newline.
+/tmp/times.klg:39:1: ErrorBar: Error: Short explanation.`, text)
+
+	stdinText := FormatError(sampleFileErrors(""), ERROR_FORMAT_GCC, false).Error()
+	assert.Contains(t, stdinText, "<stdin>:2:9: ErrorFoo: ")
+}
+
+func TestFormatParserErrorAsJson(t *testing.T) {
+	text := FormatError(sampleFileErrors("/tmp/times.klg"), ERROR_FORMAT_JSON, false).Error()
+	assert.Equal(t, `{"errors":[`+
+		`{"file":"/tmp/times.klg","line":2,"column":9,"length":3,"code":"ErrorFoo","title":"Some Title","details":"A description\nwith a newline."},`+
+		`{"file":"/tmp/times.klg","line":39,"column":1,"length":4,"code":"ErrorBar","title":"Error","details":"Short explanation."}]}`, text)
+
+	stdinText := FormatError(sampleFileErrors(""), ERROR_FORMAT_JSON, false).Error()
+	assert.Contains(t, stdinText, `{"errors":[{"file":null,"line":2,`)
+}
+
+func TestFormatErrorFallsBackToText(t *testing.T) {
+	appErr := app.NewError("Some message", "A more detailed explanation", nil)
+	assert.Equal(t, PrettifyError(appErr, false), FormatError(appErr, ERROR_FORMAT_JSON, false))
+
+	parserErr := sampleFileErrors("/tmp/times.klg")
+	assert.Equal(t, PrettifyError(parserErr, false), FormatError(parserErr, ERROR_FORMAT_TEXT, false))
+}
@@ -6,6 +6,10 @@ import (
 	"github.com/jotaen/klog/src/parser"
 )
 
+// STDIO is the file argument for reading the records from stdin, and for
+// printing the result to stdout, instead of writing it to a file.
+const STDIO app.FileOrBookmarkName = "-"
+
 type ReconcilerChain struct {
 	File app.FileOrBookmarkName
 	Ctx  app.Context
@@ -18,25 +22,25 @@ func (e NotEligibleError) Error() string { return "No record found at that date"
 func (c ReconcilerChain) Apply(
 	applicators ...func(pr *parser.ParseResult) (*parser.ReconcileResult, error),
 ) error {
+	if c.File == STDIO {
+		pr, err := c.Ctx.ReadStdinInput()
+		if err != nil {
+			return err
+		}
+		result, err := reconcile(pr, applicators)
+		if err != nil {
+			return err
+		}
+		// The output is meant to replace the input (e.g. in a pipeline),
+		// so nothing else must be printed.
+		c.Ctx.Print(result.NewText)
+		return nil
+	}
 	pr, targetFilePath, err := c.Ctx.ReadFileInput(c.File)
 	if err != nil {
 		return err
 	}
-	result, err := func() (*parser.ReconcileResult, error) {
-		for i, a := range applicators {
-			result, err := a(pr)
-			if result != nil {
-				return result, nil
-			}
-			_, isNotEligibleError := err.(NotEligibleError)
-			if isNotEligibleError && i < len(applicators)-1 {
-				// Try next reconcile function
-				continue
-			}
-			return nil, err
-		}
-		return nil, errors.New("No applicable record found")
-	}()
+	result, err := reconcile(pr, applicators)
 	if err != nil {
 		return err
 	}
@@ -47,3 +51,22 @@ func (c ReconcilerChain) Apply(
 	c.Ctx.Print("\n" + c.Ctx.Serialiser().SerialiseRecords(result.NewRecord) + "\n")
 	return nil
 }
+
+func reconcile(
+	pr *parser.ParseResult,
+	applicators []func(pr *parser.ParseResult) (*parser.ReconcileResult, error),
+) (*parser.ReconcileResult, error) {
+	for i, a := range applicators {
+		result, err := a(pr)
+		if result != nil {
+			return result, nil
+		}
+		_, isNotEligibleError := err.(NotEligibleError)
+		if isNotEligibleError && i < len(applicators)-1 {
+			// Try next reconcile function
+			continue
+		}
+		return nil, err
+	}
+	return nil, errors.New("No applicable record found")
+}
@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/lsp"
+	"os"
+)
+
+type Lsp struct{}
+
+func (opt *Lsp) Help() string {
+	return `Starts a language server, which communicates via stdin and stdout according to the Language Server Protocol (LSP).
+This command is not supposed to be invoked manually; instead, configure your editor to launch it for .klg files.
+
+The language server reports syntax errors and warnings (e.g. overlapping time ranges), and it shows the total times of the records as inlay hints and on hover.
+It also completes tags (after typing #), formats the file, and offers code actions for closing open time ranges.`
+}
+
+func (opt *Lsp) Run(ctx app.Context) error {
+	return lsp.NewServer(ctx.Now).Run(os.Stdin, os.Stdout)
+}
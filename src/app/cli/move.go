@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/parser"
+	"strings"
+)
+
+type Move struct {
+	lib.FilterArgs
+	Source app.FileOrBookmarkName `arg type:"string" name:"source" help:".klg file or bookmark to move the records from"`
+	Target app.FileOrBookmarkName `arg type:"string" name:"target" help:".klg file or bookmark to move the records to"`
+	lib.NoStyleArgs
+}
+
+func (opt *Move) Help() string {
+	return `All records that match the filter are removed from the source file and inserted into the target file.
+In the target file, every record is placed at its chronological position.
+
+If the target file already contains a record at the same date as one of the moved records, nothing is changed.
+
+Examples:
+    klog move --period=2021 work.klg work-2021.klg
+    klog move --tag=clientA @work @clientA`
+}
+
+func (opt *Move) Run(ctx app.Context) error {
+	opt.NoStyleArgs.Apply(&ctx)
+	if opt.FilterArgs.IsEmpty() {
+		return errors.New("Please specify which records to move, e.g. via --date or --period")
+	}
+	sourcePr, source, err := ctx.ReadFileInput(opt.Source)
+	if err != nil {
+		return err
+	}
+	targetPr, target, err := ctx.ReadFileInput(opt.Target)
+	if err != nil {
+		return err
+	}
+	if source == nil || target == nil {
+		return errors.New("Please specify both a source and a target file")
+	}
+	if source.Path() == target.Path() {
+		return errors.New("Source and target must be different files")
+	}
+
+	now := ctx.Now()
+	remover := parser.NewRemovalReconciler(sourcePr, func(r Record) bool {
+		return opt.FilterArgs.IsMatch(now, r)
+	})
+	if remover == nil {
+		ctx.Print("No matching records found.\n")
+		return nil
+	}
+	rs := remover.Records()
+	if collisions := findCollisions(rs, targetPr.Records); len(collisions) > 0 {
+		return errors.New("The target file already contains records at these dates: " + strings.Join(collisions, ", "))
+	}
+
+	originalTargetText := targetPr.Text()
+	newTargetText := originalTargetText
+	for i, block := range remover.Blocks() {
+		result, err := parser.NewBlockReconciler(targetPr, rs[i].Date()).InsertBlock(block)
+		if err != nil {
+			return err
+		}
+		newTargetText = result.NewText
+		targetPr, _ = parser.Parse(newTargetText)
+	}
+	sourceResult, err := remover.RemoveRecords()
+	if err != nil {
+		return err
+	}
+
+	// Write the target first, so that the records are never lost. If the
+	// source cannot be updated afterwards, the target is reverted.
+	err = ctx.WriteFile(target, newTargetText)
+	if err != nil {
+		return err
+	}
+	err = ctx.WriteFile(source, sourceResult.NewText)
+	if err != nil {
+		rollbackErr := ctx.WriteFile(target, originalTargetText)
+		if rollbackErr != nil {
+			return app.NewErrorWithCode(
+				app.IO_ERROR,
+				"Cannot move records",
+				"Writing the source file failed, and the target file could not be reverted either.\n"+
+					"Please check "+target.Path()+" for duplicate records.",
+				err,
+			)
+		}
+		return err
+	}
+	ctx.Print("\n" + ctx.Serialiser().SerialiseRecords(rs...) + "\n")
+	ctx.Print(fmt.Sprintf("Moved %d record(s) to %s\n", len(rs), target.Path()))
+	return nil
+}
+
+func findCollisions(rs []Record, existing []Record) []string {
+	var collisions []string
+	for _, r := range rs {
+		for _, e := range existing {
+			if r.Date().IsEqualTo(e.Date()) {
+				collisions = append(collisions, r.Date().ToString())
+				break
+			}
+		}
+	}
+	return collisions
+}
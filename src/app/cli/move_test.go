@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMoveRecordsIntoChronologicalPosition(t *testing.T) {
+	state, err := NewTestingContext()._SetFile("/source.klg", `
+2000-01-02
+	2h #foo
+
+2000-01-03
+	3h
+
+2000-01-04
+	4h #foo
+`)._SetFile("/target.klg", `
+2000-01-01
+	1h
+
+2000-01-03
+	3h
+`)._Run((&Move{
+		FilterArgs: lib.FilterArgs{Tags: []string{"foo"}},
+		Source:     "/source.klg",
+		Target:     "/target.klg",
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2000-01-03
+	3h
+`, state.writtenFiles["/source.klg"])
+	assert.Equal(t, `
+2000-01-01
+	1h
+
+2000-01-02
+	2h #foo
+
+2000-01-03
+	3h
+
+2000-01-04
+	4h #foo
+`, state.writtenFiles["/target.klg"])
+}
+
+func TestMoveFailsOnDateCollision(t *testing.T) {
+	state, err := NewTestingContext()._SetFile("/source.klg", `
+2000-01-01
+	2h
+`)._SetFile("/target.klg", `
+2000-01-01
+	1h
+`)._Run((&Move{
+		FilterArgs: lib.FilterArgs{Date: []klog.Date{klog.Ɀ_Date_(2000, 1, 1)}},
+		Source:     "/source.klg",
+		Target:     "/target.klg",
+	}).Run)
+	require.Error(t, err)
+	assert.Len(t, state.writtenFiles, 0)
+}
+
+func TestMoveRequiresDifferentFiles(t *testing.T) {
+	state, err := NewTestingContext()._SetFile("/source.klg", `
+2000-01-01
+	2h
+`)._Run((&Move{
+		FilterArgs: lib.FilterArgs{Today: true},
+		Source:     "/source.klg",
+		Target:     "/source.klg",
+	}).Run)
+	require.Error(t, err)
+	assert.Len(t, state.writtenFiles, 0)
+}
+
+func TestMoveDoesNotRollBackIfCommitFails(t *testing.T) {
+	ctx, repo := newContextWithFailingGitCommit(t)
+	source := filepath.Join(repo, "source.klg")
+	target := filepath.Join(repo, "target.klg")
+	require.Nil(t, os.WriteFile(source, []byte("2000-01-01\n\t1h\n\n2000-01-02\n\t2h\n"), 0600))
+	require.Nil(t, os.WriteFile(target, []byte("2000-01-03\n\t3h\n"), 0600))
+
+	err := (&Move{
+		FilterArgs: lib.FilterArgs{Date: []klog.Date{klog.Ɀ_Date_(2000, 1, 2)}},
+		Source:     app.FileOrBookmarkName(source),
+		Target:     app.FileOrBookmarkName(target),
+	}).Run(ctx)
+	require.Nil(t, err)
+	sourceContents, _ := os.ReadFile(source)
+	assert.Equal(t, "2000-01-01\n\t1h\n", string(sourceContents))
+	targetContents, _ := os.ReadFile(target)
+	assert.Equal(t, "2000-01-02\n\t2h\n\n2000-01-03\n\t3h\n", string(targetContents))
+}
@@ -6,6 +6,7 @@ import (
 )
 
 type Print struct {
+	Comments bool `name:"comments" help:"Include comments (all of a record’s comments are printed above its headline)"`
 	lib.FilterArgs
 	lib.SortArgs
 	lib.WarnArgs
@@ -14,11 +15,15 @@ type Print struct {
 }
 
 func (opt *Print) Help() string {
-	return `The output is syntax-highlighted and the formatting is slightly sanitised.`
+	return `The output is syntax-highlighted and the formatting is slightly sanitised.
+
+With --comments, the comments of a record are included as well. Note that they are all printed above the record’s headline,
+even if they appear between the entries in the file.`
 }
 
 func (opt *Print) Run(ctx app.Context) error {
 	opt.NoStyleArgs.Apply(&ctx)
+	opt.FilterArgs.Apply(&ctx)
 	records, err := ctx.ReadInputs(opt.File...)
 	if err != nil {
 		return err
@@ -29,7 +34,11 @@ func (opt *Print) Run(ctx app.Context) error {
 	now := ctx.Now()
 	records = opt.ApplyFilter(now, records)
 	records = opt.ApplySort(records)
-	ctx.Print("\n" + ctx.Serialiser().SerialiseRecords(records...) + "\n")
+	serialised := ctx.Serialiser().SerialiseRecords(records...)
+	if opt.Comments {
+		serialised = ctx.Serialiser().SerialiseRecordsWithComments(records...)
+	}
+	ctx.Print("\n" + serialised + "\n")
 
 	ctx.Print(opt.WarnArgs.ToString(now, records))
 	return nil
@@ -26,3 +26,29 @@ Hello #world
 
 `, state.printBuffer)
 }
+
+func TestPrintOutRecordWithComments(t *testing.T) {
+	records := `
+// Invoice sent
+2018-01-31
+	1h
+	// Needs review
+`
+	state, err := NewTestingContext()._SetRecords(records)._Run((&Print{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2018-01-31
+    1h
+
+`, state.printBuffer)
+
+	state, err = NewTestingContext()._SetRecords(records)._Run((&Print{Comments: true}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+// Invoice sent
+// Needs review
+2018-01-31
+    1h
+
+`, state.printBuffer)
+}
@@ -7,22 +7,37 @@ import (
 	"github.com/jotaen/klog/src/app/cli/lib"
 	"github.com/jotaen/klog/src/app/cli/report"
 	"github.com/jotaen/klog/src/service"
+	"sort"
 	"strings"
 )
 
 type Report struct {
 	AggregateBy string `name:"aggregate" short:"a" help:"Aggregate data by: day, week, month, quarter, year" enum:"DAY,day,d,WEEK,week,w,MONTH,month,m,QUARTER,quarter,q,YEAR,year,y," default:"day"`
 	Fill        bool   `name:"fill" short:"f" help:"Fill the gaps and show a consecutive stream"`
+	Balance     bool   `name:"balance" short:"b" help:"Show the running flex-time balance (implies --diff)"`
 	lib.DiffArgs
 	lib.FilterArgs
 	lib.WarnArgs
 	lib.NowArgs
+	lib.ChartArgs
 	lib.NoStyleArgs
 	lib.InputFilesArgs
 }
 
+func (opt *Report) Help() string {
+	return `With --diff, the report shows the difference between the total and the should-total time of each row.
+
+With --balance, it also shows the running flex-time balance, i.e. the accumulated difference up to that row.
+The balance column is opt-in, so that the regular --diff report keeps its layout.
+Like with 'klog balance', the balance is always based on all records (including bookmarked archives), regardless of the filter.`
+}
+
 func (opt *Report) Run(ctx app.Context) error {
+	if opt.Balance {
+		opt.Diff = true
+	}
 	opt.NoStyleArgs.Apply(&ctx)
+	opt.FilterArgs.Apply(&ctx)
 	records, err := ctx.ReadInputs(opt.File...)
 	if err != nil {
 		return err
@@ -31,34 +46,106 @@ func (opt *Report) Run(ctx app.Context) error {
 		return nil
 	}
 	now := ctx.Now()
+	config, err := opt.DiffArgs.Config(ctx, opt.File...)
+	if err != nil {
+		return err
+	}
+	if !opt.Diff && aggregationCategory(opt.AggregateBy) == "d" {
+		// The holidays are only annotated on a best-effort basis, so a
+		// broken config doesn’t prevent the report from being shown.
+		if c, cErr := ctx.ReadConfig(opt.File...); cErr == nil {
+			config = c
+		}
+	}
+	var schedule *service.Schedule
+	if opt.Diff {
+		schedule = config.Schedule
+	}
+	balances := make(map[report.Hash]Duration)
+	aggregator := newAggregator(opt.AggregateBy, config.Schedule)
+	if opt.Balance {
+		// Like with `klog balance`, the balance is based on all records,
+		// including the archives, not just on the filtered ones.
+		ctx.IncludeArchives(func(int) bool { return true })
+		allRecords, err := ctx.ReadInputs(opt.File...)
+		if err != nil {
+			return err
+		}
+		for _, step := range service.RunningBalance(config.Balance, schedule, allRecords...) {
+			balances[aggregator.DateHash(step.Date)] = step.Balance
+		}
+	}
+	allRecords := records
 	records = opt.ApplyFilter(now, records)
 	records = service.Sort(records, true)
-	aggregator := opt.findAggregator()
 	recordGroups, dates := groupByDate(aggregator.DateHash, records)
 	if opt.Fill {
 		dates = allDatesRange(records[0].Date(), records[len(records)-1].Date())
 	}
 
+	// Days without any record count as owed time, if the schedule requires it.
+	// Records that were filtered out (e.g. due to other tags) are still records,
+	// so the unrecorded days are determined based on all of them.
+	var unrecordedDays []Date
+	if len(records) > 0 {
+		unrecordedDays = schedule.UnrecordedDays(records[0].Date(), records[len(records)-1].Date(), allRecords...)
+	}
+	unrecordedShould := make(map[report.Hash]Duration)
+	for _, d := range unrecordedDays {
+		hash := aggregator.DateHash(d)
+		if unrecordedShould[hash] == nil {
+			unrecordedShould[hash] = NewDuration(0, 0)
+		}
+		unrecordedShould[hash] = unrecordedShould[hash].Plus(schedule.ShouldTotalAt(d))
+	}
+	if !opt.Fill && len(unrecordedDays) > 0 {
+		dates = mergeDates(dates, unrecordedDays)
+	}
+
+	// Days off (e.g. vacation) are credited in a separate column.
+	grandCredit := NewDuration(0, 0)
+	if opt.Diff {
+		grandCredit = service.CreditSum(schedule, records...)
+	}
+	hasCredit := grandCredit.InMinutes() != 0
+
 	// Table setup
 	numberOfValueColumns := func() int {
+		n := 1
+		if hasCredit {
+			n++
+		}
 		if opt.Diff {
-			return 3
+			n += 2
 		}
-		return 1
+		if opt.Balance {
+			n++
+		}
+		return n
 	}()
 	table := terminalformat.NewTable(
 		aggregator.NumberOfPrefixColumns()+numberOfValueColumns,
 		" ",
 	)
 
+	chart := terminalformat.NewBarChart(opt.IsStyled())
+
 	// Header
 	aggregator.OnHeaderPrefix(table)
+	chart.Skip(1)
 	table.CellR("   Total")
+	if hasCredit {
+		table.CellR("  Credit")
+	}
 	if opt.Diff {
 		table.CellR("   Should").CellR("    Diff")
 	}
+	if opt.Balance {
+		table.CellR(" Balance")
+	}
 
 	// Rows
+	var lastBalance Duration
 	hashesAlreadyProcessed := make(map[report.Hash]bool)
 	for _, date := range dates {
 		hash := aggregator.DateHash(date)
@@ -68,52 +155,103 @@ func (opt *Report) Run(ctx app.Context) error {
 		hashesAlreadyProcessed[hash] = true
 		aggregator.OnRowPrefix(table, date)
 		rs := recordGroups[hash]
-		if len(rs) == 0 {
+		if len(rs) == 0 && unrecordedShould[hash] == nil {
 			table.Skip(numberOfValueColumns)
+			chart.Skip(1)
 			continue
 		}
 
 		total := opt.NowArgs.Total(now, rs...)
 		table.CellR(ctx.Serialiser().Duration(total))
 
-		if opt.Diff {
-			should := service.ShouldTotalSum(rs...)
-			diff := service.Diff(should, total)
-			table.CellR(ctx.Serialiser().ShouldTotal(should)).CellR(ctx.Serialiser().SignedDuration(diff))
+		credit := service.CreditSum(schedule, rs...)
+		if hasCredit {
+			if credit.InMinutes() != 0 {
+				table.CellR(ctx.Serialiser().Duration(credit))
+			} else {
+				table.Skip(1)
+			}
+		}
+
+		if !opt.Diff {
+			chart.Bar(terminalformat.Bar{Base: total.InMinutes()})
+			continue
+		}
+		should := service.ShouldTotalSum(schedule, rs...)
+		if unrecordedShould[hash] != nil {
+			should = NewShouldTotal(0, should.Plus(unrecordedShould[hash]).InMinutes())
+		}
+		diff := service.Diff(should, total.Plus(credit))
+		table.CellR(ctx.Serialiser().ShouldTotal(should)).CellR(ctx.Serialiser().SignedDuration(diff))
+		if opt.Balance {
+			if balance := balances[hash]; balance != nil {
+				table.CellR(ctx.Serialiser().SignedDuration(balance))
+				lastBalance = balance
+			} else {
+				table.Skip(1)
+			}
 		}
+		chart.Bar(diffBar(should, diff))
 	}
 
 	// Line
 	table.Skip(aggregator.NumberOfPrefixColumns()).Fill("=")
+	if hasCredit {
+		table.Fill("=")
+	}
 	if opt.Diff {
 		table.Fill("=").Fill("=")
 	}
+	if opt.Balance {
+		table.Fill("=")
+	}
 	ctx.Print("\n")
 	grandTotal := opt.NowArgs.Total(now, records...)
 
 	// Footer
 	table.Skip(aggregator.NumberOfPrefixColumns())
 	table.CellR(ctx.Serialiser().Duration(grandTotal))
+	if hasCredit {
+		table.CellR(ctx.Serialiser().Duration(grandCredit))
+	}
 	if opt.Diff {
-		grandShould := service.ShouldTotalSum(records...)
-		grandDiff := service.Diff(grandShould, grandTotal)
+		grandShould := service.ShouldTotalSum(schedule, records...)
+		for _, d := range unrecordedShould {
+			grandShould = NewShouldTotal(0, grandShould.Plus(d).InMinutes())
+		}
+		grandDiff := service.Diff(grandShould, grandTotal.Plus(grandCredit))
 		table.CellR(ctx.Serialiser().ShouldTotal(grandShould)).CellR(ctx.Serialiser().SignedDuration(grandDiff))
 	}
+	if opt.Balance {
+		// The footer shows the balance as of the last row.
+		if lastBalance == nil {
+			lastBalance = NewDuration(0, 0)
+		}
+		table.CellR(ctx.Serialiser().SignedDuration(lastBalance))
+	}
 
-	table.Collect(ctx.Print)
+	if opt.Chart {
+		chart.Collect(table, ctx.TerminalWidth(), ctx.Print)
+	} else {
+		table.Collect(ctx.Print)
+	}
 	ctx.Print(opt.WarnArgs.ToString(now, records))
 	return nil
 }
 
-func (opt *Report) findAggregator() report.Aggregator {
-	category := (func() string {
-		if opt.AggregateBy == "" {
-			return "d"
-		} else {
-			return strings.ToLower(opt.AggregateBy[:1])
-		}
-	})()
-	switch category {
+// diffBar returns a bar that shows the overtime or undertime in relation to
+// the should-total time.
+func diffBar(should ShouldTotal, diff Duration) terminalformat.Bar {
+	if diff.InMinutes() >= 0 {
+		return terminalformat.Bar{Base: should.InMinutes(), Excess: diff.InMinutes()}
+	}
+	return terminalformat.Bar{Base: should.InMinutes() + diff.InMinutes(), Deficit: -diff.InMinutes()}
+}
+
+// newAggregator returns the aggregator for the given category. If the schedule
+// contains public holidays, the day aggregator annotates them.
+func newAggregator(aggregateBy string, schedule *service.Schedule) report.Aggregator {
+	switch aggregationCategory(aggregateBy) {
 	case "y":
 		return report.NewYearAggregator()
 	case "q":
@@ -123,10 +261,28 @@ func (opt *Report) findAggregator() report.Aggregator {
 	case "w":
 		return report.NewWeekAggregator()
 	default: // "d"
+		if schedule != nil && len(schedule.Holidays) > 0 {
+			return report.NewDayAggregatorWithHolidays(func(d Date) (string, bool) {
+				h, isHoliday := schedule.HolidayAt(d)
+				if isHoliday && h.Name == "" {
+					return "Holiday", true
+				}
+				return h.Name, isHoliday
+			})
+		}
 		return report.NewDayAggregator()
 	}
 }
 
+// aggregationCategory returns the single-letter category of the aggregation,
+// which defaults to "d" (day).
+func aggregationCategory(aggregateBy string) string {
+	if aggregateBy == "" {
+		return "d"
+	}
+	return strings.ToLower(aggregateBy[:1])
+}
+
 func allDatesRange(from Date, to Date) []Date {
 	result := []Date{from}
 	for true {
@@ -139,6 +295,14 @@ func allDatesRange(from Date, to Date) []Date {
 	return result
 }
 
+func mergeDates(ds1 []Date, ds2 []Date) []Date {
+	result := append(append([]Date(nil), ds1...), ds2...)
+	sort.Slice(result, func(i, j int) bool {
+		return result[j].IsAfterOrEqual(result[i])
+	})
+	return result
+}
+
 func groupByDate(hashProvider func(Date) report.Hash, rs []Record) (map[report.Hash][]Record, []Date) {
 	days := make(map[report.Hash][]Record, len(rs))
 	var order []Date
@@ -9,15 +9,25 @@ import (
 )
 
 type dayAggregator struct {
-	y int
-	m int
+	y         int
+	m         int
+	holidayAt func(Date) (string, bool)
 }
 
 func NewDayAggregator() Aggregator {
-	return &dayAggregator{-1, -1}
+	return &dayAggregator{-1, -1, nil}
+}
+
+// NewDayAggregatorWithHolidays adds a column that annotates public holidays.
+// The lookup function returns the name of the holiday at the given date.
+func NewDayAggregatorWithHolidays(holidayAt func(Date) (string, bool)) Aggregator {
+	return &dayAggregator{-1, -1, holidayAt}
 }
 
 func (a *dayAggregator) NumberOfPrefixColumns() int {
+	if a.holidayAt != nil {
+		return 5
+	}
 	return 4
 }
 
@@ -31,6 +41,9 @@ func (a *dayAggregator) OnHeaderPrefix(table *terminalformat.Table) {
 		CellL("   ").    // Dec
 		CellL("      "). // Sun
 		CellR("   ")     // 17.
+	if a.holidayAt != nil {
+		table.CellL("") // Christmas
+	}
 }
 
 func (a *dayAggregator) OnRowPrefix(table *terminalformat.Table, date Date) {
@@ -53,4 +66,13 @@ func (a *dayAggregator) OnRowPrefix(table *terminalformat.Table, date Date) {
 
 	// Day
 	table.CellR(lib.PrettyDay(date.Weekday())[:3]).CellR(fmt.Sprintf("%2v.", date.Day()))
+
+	// Holiday
+	if a.holidayAt != nil {
+		if name, isHoliday := a.holidayAt(date); isHoliday {
+			table.CellL(name)
+		} else {
+			table.Skip(1)
+		}
+	}
 }
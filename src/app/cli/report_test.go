@@ -1,7 +1,10 @@
 package cli
 
 import (
+	"github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
 	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -105,6 +108,196 @@ func TestDayReportWithDiff(t *testing.T) {
 `, state.printBuffer)
 }
 
+func TestDayReportWithDiffFromSchedule(t *testing.T) {
+	state, err := NewTestingContext()._SetConfig(app.Config{
+		Schedule: &service.Schedule{Periods: []service.SchedulePeriod{{
+			Since: klog.Ɀ_Date_(2018, 1, 1),
+			Hours: map[int]klog.Duration{1: klog.NewDuration(8, 0), 2: klog.NewDuration(8, 0), 3: klog.NewDuration(4, 0)},
+		}}},
+	})._SetRecords(`
+2018-07-09
+	8h
+
+2018-07-11 (2h!)
+	3h
+`)._Run((&Report{DiffArgs: lib.DiffArgs{Diff: true}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+                       Total    Should     Diff
+2018 Jul    Mon  9.       8h       8h!       0m
+            Tue 10.       0m       8h!      -8h
+            Wed 11.       3h       2h!      +1h
+                    ======== ========= ========
+                         11h      18h!      -7h
+`, state.printBuffer)
+}
+
+func TestDayReportWithDiffFromScheduleWithoutMatchingRecords(t *testing.T) {
+	state, err := NewTestingContext()._SetConfig(app.Config{
+		Schedule: &service.Schedule{Periods: []service.SchedulePeriod{{
+			Since: klog.Ɀ_Date_(2018, 1, 1),
+			Hours: map[int]klog.Duration{1: klog.NewDuration(8, 0)},
+		}}},
+	})._SetRecords(`
+2018-07-09
+	8h
+`)._Run((&Report{DiffArgs: lib.DiffArgs{Diff: true}, FilterArgs: lib.FilterArgs{Tags: []string{"foo"}}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+                       Total    Should     Diff
+                    ======== ========= ========
+                          0m       0m!       0m
+`, state.printBuffer)
+}
+
+func TestDayReportWithDiffFromScheduleAndTagFilter(t *testing.T) {
+	state, err := NewTestingContext()._SetConfig(app.Config{
+		Schedule: &service.Schedule{Periods: []service.SchedulePeriod{{
+			Since: klog.Ɀ_Date_(2018, 1, 1),
+			Hours: map[int]klog.Duration{1: klog.NewDuration(8, 0), 2: klog.NewDuration(8, 0), 3: klog.NewDuration(8, 0)},
+		}}},
+	})._SetRecords(`
+2018-07-09
+	8h #a
+
+2018-07-10
+	8h #b
+
+2018-07-11
+	7h #a
+`)._Run((&Report{DiffArgs: lib.DiffArgs{Diff: true}, FilterArgs: lib.FilterArgs{Tags: []string{"a"}}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+                       Total    Should     Diff
+2018 Jul    Mon  9.       8h       8h!       0m
+            Wed 11.       7h       8h!      -1h
+                    ======== ========= ========
+                         15h      16h!      -1h
+`, state.printBuffer)
+}
+
+func TestDayReportWithCreditForDaysOff(t *testing.T) {
+	state, err := NewTestingContext()._SetConfig(app.Config{
+		Schedule: &service.Schedule{Periods: []service.SchedulePeriod{{
+			Since: klog.Ɀ_Date_(2018, 1, 1),
+			Hours: map[int]klog.Duration{1: klog.NewDuration(8, 0), 2: klog.NewDuration(8, 0)},
+		}}},
+	})._SetRecords(`
+2018-07-09
+#vacation
+
+2018-07-10
+	7h
+`)._Run((&Report{DiffArgs: lib.DiffArgs{Diff: true}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+                       Total   Credit    Should     Diff
+2018 Jul    Mon  9.       0m       8h       8h!       0m
+            Tue 10.       7h                8h!      -1h
+                    ======== ======== ========= ========
+                          7h       8h      16h!      -1h
+`, state.printBuffer)
+}
+
+func TestDayReportAnnotatesHolidays(t *testing.T) {
+	state, err := NewTestingContext()._SetConfig(app.Config{
+		Schedule: &service.Schedule{
+			Periods: []service.SchedulePeriod{{
+				Since: klog.Ɀ_Date_(2018, 1, 1),
+				Hours: map[int]klog.Duration{1: klog.NewDuration(8, 0), 2: klog.NewDuration(8, 0)},
+			}},
+			Holidays: []service.Holiday{{Date: klog.Ɀ_Date_(2018, 12, 24), Name: "Christmas Eve"}, {Date: klog.Ɀ_Date_(2018, 12, 25)}},
+		},
+	})._SetRecords(`
+2018-12-24
+	1h
+
+2018-12-25
+	2h
+
+2018-12-31
+	8h
+`)._Run((&Report{DiffArgs: lib.DiffArgs{Diff: true}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+                                     Total    Should     Diff
+2018 Dec    Mon 24. Christmas Eve       1h       0m!      +1h
+            Tue 25. Holiday             2h       0m!      +2h
+            Mon 31.                     8h       8h!       0m
+                                  ======== ========= ========
+                                       11h       8h!      +3h
+`, state.printBuffer)
+}
+
+func TestDayReportIgnoresBrokenConfigWithoutDiff(t *testing.T) {
+	state, err := NewTestingContext()._SetConfigError(
+		app.NewErrorWithCode(app.CONFIG_ERROR, "Invalid config", "", nil),
+	)._SetRecords(`
+2018-12-24
+	1h
+`)._Run((&Report{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+                       Total
+2018 Dec    Mon 24.       1h
+                    ========
+                          1h
+`, state.printBuffer)
+}
+
+func TestDayReportWithChart(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2018-07-09 (8h!)
+	10h
+
+2018-07-10 (8h!)
+	4h
+
+2018-07-12 (8h!)
+	8h
+`)._Run((&Report{
+		DiffArgs:    lib.DiffArgs{Diff: true},
+		Fill:        true,
+		ChartArgs:   lib.ChartArgs{Chart: true},
+		NoStyleArgs: lib.NoStyleArgs{NoStyle: true},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+                       Total    Should     Diff
+2018 Jul    Mon  9.      10h       8h!      +2h ##########++
+            Tue 10.       4h       8h!      -4h #####-----
+            Wed 11.                            
+            Thu 12.       8h       8h!       0m ##########
+                    ======== ========= ========
+                         22h      24h!      -2h
+`, state.printBuffer)
+}
+
+func TestDayReportWithBalance(t *testing.T) {
+	state, err := NewTestingContext()._SetArchivedRecords(`
+2017-12-29 (8h!)
+	9h
+`)._SetRecords(`
+2018-07-07 (8h!)
+	8h
+
+2018-07-08 (5h30m!)
+	2h
+
+2018-07-09 (2h!)
+	5h20m
+`)._Run((&Report{Balance: true}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+                       Total    Should     Diff  Balance
+2018 Jul    Sat  7.       8h       8h!       0m      +1h
+            Sun  8.       2h    5h30m!   -3h30m   -2h30m
+            Mon  9.    5h20m       2h!   +3h20m     +50m
+                    ======== ========= ======== ========
+                      15h20m   15h30m!     -10m     +50m
+`, state.printBuffer)
+}
+
 func TestWeekReport(t *testing.T) {
 	state, err := NewTestingContext()._SetRecords(`
 2018-03-02 (8h!)
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/jotaen/klog/lib/jotaen/terminalformat"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/service"
+	"sort"
+	"strconv"
+)
+
+type Stats struct {
+	Format string `name:"format" help:"Output format: text, json" enum:"text,json" default:"text"`
+	lib.FilterArgs
+	lib.NoStyleArgs
+	lib.InputFilesArgs
+}
+
+func (opt *Stats) Help() string {
+	return `Computes statistics about the records:
+- The number of days tracked
+- The mean and median total time per weekday
+- The distributions of the first start time and of the last end time per day (by hour)
+- The longest streaks of consecutive days with time (with and without counting weekends)
+- The number of entries per tag
+
+Records at the same date are evaluated as one day.`
+}
+
+func (opt *Stats) Run(ctx app.Context) error {
+	opt.NoStyleArgs.Apply(&ctx)
+	opt.FilterArgs.Apply(&ctx)
+	records, err := ctx.ReadInputs(opt.File...)
+	if err != nil {
+		return err
+	}
+	records = opt.ApplyFilter(ctx.Now(), records)
+	stats := service.CalculateStats(records...)
+	if opt.Format == "json" {
+		ctx.Print(statsToJson(stats) + "\n")
+		return nil
+	}
+	if stats.NumberOfDays == 0 {
+		return nil
+	}
+	opt.printText(ctx, stats)
+	return nil
+}
+
+func (opt *Stats) printText(ctx app.Context, stats service.Stats) {
+	ctx.Print(fmt.Sprintf("Days tracked: %d\n\n", stats.NumberOfDays))
+
+	// Weekdays
+	weekdays := terminalformat.NewTable(4, " ")
+	weekdays.CellL("         ").CellR("Days").CellR("   Mean").CellR(" Median")
+	for weekday := 1; weekday <= 7; weekday++ {
+		s, ok := stats.Weekdays[weekday]
+		weekdays.CellL(lib.PrettyDay(weekday))
+		if !ok {
+			weekdays.CellR("0").Skip(2)
+			continue
+		}
+		weekdays.
+			CellR(fmt.Sprint(s.NumberOfDays)).
+			CellR(ctx.Serialiser().Duration(s.Mean)).
+			CellR(ctx.Serialiser().Duration(s.Median))
+	}
+	weekdays.Collect(ctx.Print)
+
+	// Streaks
+	ctx.Print("\n")
+	dash := "–"
+	if !opt.IsStyled() {
+		dash = "-"
+	}
+	streaks := terminalformat.NewTable(2, " ")
+	for _, s := range []struct {
+		label  string
+		streak service.Streak
+	}{
+		{"Longest streak:", stats.LongestStreak},
+		{"Longest weekday streak:", stats.LongestWeekdayStreak},
+	} {
+		streaks.CellL(s.label)
+		if s.streak.Days == 0 {
+			streaks.CellL("-")
+			continue
+		}
+		streaks.CellL(fmt.Sprintf(
+			"%d day%s (%s %s %s)",
+			s.streak.Days,
+			func() string {
+				if s.streak.Days == 1 {
+					return ""
+				}
+				return "s"
+			}(),
+			ctx.Serialiser().Date(s.streak.From),
+			dash,
+			ctx.Serialiser().Date(s.streak.Until),
+		))
+	}
+	streaks.Collect(ctx.Print)
+
+	// Times
+	for _, d := range []struct {
+		label        string
+		median       Time
+		distribution map[int]int
+	}{
+		{"Start times", stats.MedianStart, stats.StartTimes},
+		{"End times", stats.MedianEnd, stats.EndTimes},
+	} {
+		if d.median == nil {
+			continue
+		}
+		ctx.Print("\n" + d.label + " (median " + ctx.Serialiser().Time(d.median) + ")\n")
+		table := terminalformat.NewTable(2, " ")
+		chart := terminalformat.NewBarChart(opt.IsStyled())
+		for _, hour := range sortedKeys(d.distribution) {
+			table.CellR(fmt.Sprintf("%d:00", hour)).CellR(fmt.Sprint(d.distribution[hour]))
+			chart.Bar(terminalformat.Bar{Base: d.distribution[hour]})
+		}
+		chart.Collect(table, ctx.TerminalWidth(), ctx.Print)
+	}
+
+	// Tags
+	if len(stats.EntriesPerTag) > 0 {
+		ctx.Print("\nEntries per tag\n")
+		tags := terminalformat.NewTable(2, " ")
+		for _, t := range sortTagCounts(stats.EntriesPerTag) {
+			tags.CellL(t.ToString()).CellR(fmt.Sprint(stats.EntriesPerTag[t]))
+		}
+		tags.Collect(ctx.Print)
+	}
+}
+
+type statsJson struct {
+	Days                 int                `json:"days"`
+	Weekdays             []weekdayStatsJson `json:"weekdays"`
+	StartTimes           timesJson          `json:"start_times"`
+	EndTimes             timesJson          `json:"end_times"`
+	LongestStreak        *streakJson        `json:"longest_streak"`
+	LongestWeekdayStreak *streakJson        `json:"longest_weekday_streak"`
+	EntriesPerTag        map[string]int     `json:"entries_per_tag"`
+}
+
+type weekdayStatsJson struct {
+	Weekday    string `json:"weekday"`
+	Days       int    `json:"days"`
+	Mean       string `json:"mean"`
+	MeanMins   int    `json:"mean_mins"`
+	Median     string `json:"median"`
+	MedianMins int    `json:"median_mins"`
+}
+
+type timesJson struct {
+	Median  *string        `json:"median"`
+	PerHour map[string]int `json:"per_hour"`
+}
+
+type streakJson struct {
+	From  string `json:"from"`
+	Until string `json:"until"`
+	Days  int    `json:"days"`
+}
+
+func statsToJson(stats service.Stats) string {
+	view := statsJson{
+		Days:                 stats.NumberOfDays,
+		Weekdays:             []weekdayStatsJson{},
+		StartTimes:           toTimesJson(stats.MedianStart, stats.StartTimes),
+		EndTimes:             toTimesJson(stats.MedianEnd, stats.EndTimes),
+		LongestStreak:        toStreakJson(stats.LongestStreak),
+		LongestWeekdayStreak: toStreakJson(stats.LongestWeekdayStreak),
+		EntriesPerTag:        make(map[string]int),
+	}
+	for weekday := 1; weekday <= 7; weekday++ {
+		s, ok := stats.Weekdays[weekday]
+		if !ok {
+			continue
+		}
+		view.Weekdays = append(view.Weekdays, weekdayStatsJson{
+			Weekday:    lib.PrettyDay(weekday),
+			Days:       s.NumberOfDays,
+			Mean:       s.Mean.ToString(),
+			MeanMins:   s.Mean.InMinutes(),
+			Median:     s.Median.ToString(),
+			MedianMins: s.Median.InMinutes(),
+		})
+	}
+	for t, count := range stats.EntriesPerTag {
+		view.EntriesPerTag[string(t)] = count
+	}
+	buffer := new(bytes.Buffer)
+	enc := json.NewEncoder(buffer)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(&view)
+	if err != nil {
+		panic(err) // This should never happen
+	}
+	return string(bytes.TrimRight(buffer.Bytes(), "\n"))
+}
+
+func toTimesJson(median Time, distribution map[int]int) timesJson {
+	result := timesJson{PerHour: make(map[string]int)}
+	if median != nil {
+		m := median.ToString()
+		result.Median = &m
+	}
+	for hour, count := range distribution {
+		result.PerHour[strconv.Itoa(hour)] = count
+	}
+	return result
+}
+
+func toStreakJson(s service.Streak) *streakJson {
+	if s.Days == 0 {
+		return nil
+	}
+	return &streakJson{From: s.From.ToString(), Until: s.Until.ToString(), Days: s.Days}
+}
+
+func sortedKeys(m map[int]int) []int {
+	var result []int
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Ints(result)
+	return result
+}
+
+func sortTagCounts(counts map[Tag]int) []Tag {
+	var result []Tag
+	for t := range counts {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i int, j int) bool {
+		return result[i] < result[j]
+	})
+	return result
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const statsSampleRecords = `
+2021-03-01
+	8:00 - 12:00 #work
+	13:00 - 17:00
+
+2021-03-02
+	9:30 - 15:30 #work #meeting
+
+2021-03-08
+	8:30-12:30
+`
+
+func TestPrintStats(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(statsSampleRecords)._Run((&Stats{
+		Format:      "text",
+		NoStyleArgs: lib.NoStyleArgs{NoStyle: true},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+Days tracked: 3
+
+          Days    Mean  Median
+Monday       2      6h      6h
+Tuesday      1      6h      6h
+Wednesday    0                
+Thursday     0                
+Friday       0                
+Saturday     0                
+Sunday       0                
+
+Longest streak:         2 days (2021-03-01 - 2021-03-02)
+Longest weekday streak: 2 days (2021-03-01 - 2021-03-02)
+
+Start times (median 8:30)
+8:00 2 #####################################################
+9:00 1 ###########################
+
+End times (median 15:30)
+12:00 1 ####################################################
+15:00 1 ####################################################
+17:00 1 ####################################################
+
+Entries per tag
+#meeting 1
+#work    2
+`, state.printBuffer)
+}
+
+func TestPrintStatsAsJson(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(statsSampleRecords)._Run((&Stats{Format: "json"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+{"days":3,"weekdays":[{"weekday":"Monday","days":2,"mean":"6h","mean_mins":360,"median":"6h","median_mins":360},{"weekday":"Tuesday","days":1,"mean":"6h","mean_mins":360,"median":"6h","median_mins":360}],"start_times":{"median":"8:30","per_hour":{"8":2,"9":1}},"end_times":{"median":"15:30","per_hour":{"12":1,"15":1,"17":1}},"longest_streak":{"from":"2021-03-01","until":"2021-03-02","days":2},"longest_weekday_streak":{"from":"2021-03-01","until":"2021-03-02","days":2},"entries_per_tag":{"meeting":1,"work":2}}
+`, state.printBuffer)
+}
@@ -61,3 +61,14 @@ func TestStopFailsIfNoOpenRange(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, state.writtenFileContents, "")
 }
+
+func TestStopViaStdinReportsErrorWithoutOutput(t *testing.T) {
+	state, err := NewTestingContext()._SetStdin(`
+1920-02-02
+	1h
+`)._SetNow(1920, 2, 2, 15, 24)._Run((&Stop{
+		OutputFileArgs: lib.OutputFileArgs{File: lib.STDIO},
+	}).Run)
+	require.Error(t, err)
+	assert.Equal(t, "", state.printBuffer)
+}
@@ -12,12 +12,14 @@ import (
 type Tags struct {
 	lib.FilterArgs
 	lib.WarnArgs
+	lib.ChartArgs
 	lib.NoStyleArgs
 	lib.InputFilesArgs
 }
 
 func (opt *Tags) Run(ctx app.Context) error {
 	opt.NoStyleArgs.Apply(&ctx)
+	opt.FilterArgs.Apply(&ctx)
 	records, err := ctx.ReadInputs(opt.File...)
 	if err != nil {
 		return err
@@ -30,13 +32,19 @@ func (opt *Tags) Run(ctx app.Context) error {
 		return nil
 	}
 	table := terminalformat.NewTable(2, " ")
+	chart := terminalformat.NewBarChart(opt.IsStyled())
 	for _, t := range tagsOrdered {
-		es := entriesByTag[t]
+		total := service.TotalEntries(entriesByTag[t]...)
 		table.
 			CellL(t.ToString()).
-			CellL(ctx.Serialiser().Duration(service.TotalEntries(es...)))
+			CellL(ctx.Serialiser().Duration(total))
+		chart.Bar(terminalformat.Bar{Base: total.InMinutes()})
+	}
+	if opt.Chart {
+		chart.Collect(table, ctx.TerminalWidth(), ctx.Print)
+	} else {
+		table.Collect(ctx.Print)
 	}
-	table.Collect(ctx.Print)
 	ctx.Print(opt.WarnArgs.ToString(now, records))
 	return nil
 }
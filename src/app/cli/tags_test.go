@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"github.com/jotaen/klog/src/app/cli/lib"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -47,3 +48,18 @@ Was #sick, need to compensate later
 #sports    8h   
 `, state.printBuffer)
 }
+
+func TestPrintTagsWithChart(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+1995-03-17
+	3h #badminton
+	1h #running
+	30m #yoga
+`)._Run((&Tags{ChartArgs: lib.ChartArgs{Chart: true}, NoStyleArgs: lib.NoStyleArgs{NoStyle: true}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+#badminton 3h  #############################################
+#running   1h  ###############
+#yoga      30m ########
+`, state.printBuffer)
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/parser"
+	"regexp"
+	"strings"
+)
+
+type Templates struct {
+	List TemplatesList `cmd name:"list" help:"Displays all templates and their parameters"`
+	Ls   TemplatesList `cmd name:"ls" hidden help:"Alias for 'list'"`
+
+	Show TemplatesShow `cmd name:"show" help:"Prints the contents of a template"`
+
+	Create TemplatesCreate `cmd name:"create" help:"Creates a new template"`
+
+	Delete TemplatesDelete `cmd name:"delete" help:"Removes a template"`
+	Rm     TemplatesDelete `cmd name:"rm" hidden help:"Alias for 'delete'"`
+}
+
+func (opt *Templates) Help() string {
+	return `Templates are files in the klog folder (~/.klog) with the extension .template.klg, e.g. ~/.klog/daily.template.klg.
+You can create a record from a template via: klog create --template daily
+
+Templates can contain the following built-in variables: {{ TODAY }}, {{ YESTERDAY }}, {{ NOW }}, {{ WEEKDAY }} (e.g. Monday), {{ WEEK }} (the ISO week number), and {{ SHOULD }} (the should-total of the day, according to the schedule; empty if no schedule is configured).
+The date-related variables refer to the date of the new record, so they also work in conjunction with --date.
+
+All other variables are parameters, e.g. {{ project }}. Their values are passed via: klog create --template daily --var project=klog
+A parameter can also have a default value, which is declared in the template: {{ project = klog }}`
+}
+
+var templateNamePattern = regexp.MustCompile(`^[\p{L}\d_-]+$`)
+
+// checkTemplateName makes sure that the name cannot refer to a file outside
+// of the klog folder.
+func checkTemplateName(name string) app.Error {
+	if !templateNamePattern.MatchString(name) {
+		return app.NewError(
+			"Invalid template name",
+			"The name can only contain letters, digits, - and _",
+			nil,
+		)
+	}
+	return nil
+}
+
+const templateSkeleton = `{{ TODAY }}
+    {{ NOW }} - ?
+`
+
+type TemplatesList struct{}
+
+func (opt *TemplatesList) Run(ctx app.Context) error {
+	names := ctx.TemplateNames()
+	if len(names) == 0 {
+		ctx.Print("There are no templates defined yet.\n")
+		return nil
+	}
+	for _, name := range names {
+		template, err := ctx.ReadTemplate(name)
+		if err != nil {
+			return err
+		}
+		var params []string
+		for _, p := range parser.TemplateParameters(template) {
+			if p.Default != nil {
+				params = append(params, p.Name+"="+*p.Default)
+			} else {
+				params = append(params, p.Name)
+			}
+		}
+		line := name
+		if len(params) > 0 {
+			line += " (" + strings.Join(params, ", ") + ")"
+		}
+		ctx.Print(line + "\n")
+	}
+	return nil
+}
+
+type TemplatesShow struct {
+	Name string `arg name:"template" type:"string" help:"The name of the template"`
+}
+
+func (opt *TemplatesShow) Run(ctx app.Context) error {
+	if nErr := checkTemplateName(opt.Name); nErr != nil {
+		return nErr
+	}
+	template, err := ctx.ReadTemplate(opt.Name)
+	if err != nil {
+		return err
+	}
+	ctx.Print(template)
+	return nil
+}
+
+type TemplatesCreate struct {
+	Name  string `arg name:"template" type:"string" help:"The name of the template"`
+	Force bool   `name:"force" help:"Overwrite the template, if it already exists"`
+	lib.QuietArgs
+}
+
+func (opt *TemplatesCreate) Help() string {
+	return `The new template contains a basic skeleton, which you can then adjust in your editor.`
+}
+
+func (opt *TemplatesCreate) Run(ctx app.Context) error {
+	if nErr := checkTemplateName(opt.Name); nErr != nil {
+		return nErr
+	}
+	if !opt.Force {
+		if _, err := ctx.ReadTemplate(opt.Name); err == nil {
+			return app.NewError(
+				"Template already exists",
+				"Use --force to overwrite the template "+opt.Name,
+				nil,
+			)
+		}
+	}
+	err := ctx.WriteTemplate(opt.Name, templateSkeleton)
+	if err != nil {
+		return err
+	}
+	if !opt.Quiet {
+		ctx.Print("Created new template:\n")
+	}
+	ctx.Print(ctx.KlogFolder() + opt.Name + app.TEMPLATE_FILE_SUFFIX + "\n")
+	return nil
+}
+
+type TemplatesDelete struct {
+	Name string `arg name:"template" type:"string" help:"The name of the template"`
+	lib.QuietArgs
+}
+
+func (opt *TemplatesDelete) Run(ctx app.Context) error {
+	if nErr := checkTemplateName(opt.Name); nErr != nil {
+		return nErr
+	}
+	err := ctx.DeleteTemplate(opt.Name)
+	if err != nil {
+		return err
+	}
+	if !opt.Quiet {
+		ctx.Print("Removed template " + opt.Name + "\n")
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestListTemplates(t *testing.T) {
+	state, err := NewTestingContext().
+		_SetTemplate("weekly", "{{ TODAY }}\n").
+		_SetTemplate("daily", "{{ TODAY }} ({{ SHOULD }})\n#{{ project = klog }}\n    {{ start }} - ?\n").
+		_Run((&TemplatesList{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\ndaily (project=klog, start)\nweekly\n", state.printBuffer)
+}
+
+func TestListNoTemplates(t *testing.T) {
+	state, err := NewTestingContext()._Run((&TemplatesList{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\nThere are no templates defined yet.\n", state.printBuffer)
+}
+
+func TestShowTemplate(t *testing.T) {
+	ctx := NewTestingContext()._SetTemplate("daily", "{{ TODAY }}\n    1h\n")
+	state, err := ctx._Run((&TemplatesShow{Name: "daily"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n{{ TODAY }}\n    1h\n", state.printBuffer)
+
+	_, err = ctx._Run((&TemplatesShow{Name: "weekly"}).Run)
+	require.Error(t, err)
+	assert.Equal(t, "No such template", err.Error())
+}
+
+func TestCreateTemplate(t *testing.T) {
+	ctx := NewTestingContext()
+	state, err := ctx._Run((&TemplatesCreate{Name: "daily"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\nCreated new template:\n~/.klog/daily.template.klg\n", state.printBuffer)
+	assert.Equal(t, templateSkeleton, ctx.templates["daily"])
+}
+
+func TestCreateTemplateDoesNotOverwrite(t *testing.T) {
+	ctx := NewTestingContext()._SetTemplate("daily", "{{ TODAY }}\n")
+	_, err := ctx._Run((&TemplatesCreate{Name: "daily"}).Run)
+	require.Error(t, err)
+	assert.Equal(t, "Template already exists", err.Error())
+	assert.Equal(t, "{{ TODAY }}\n", ctx.templates["daily"])
+
+	_, err = ctx._Run((&TemplatesCreate{Name: "daily", Force: true}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, templateSkeleton, ctx.templates["daily"])
+}
+
+func TestTemplateCommandsRejectInvalidName(t *testing.T) {
+	for _, cmd := range []func(app.Context) error{
+		(&TemplatesCreate{Name: "../foo"}).Run,
+		(&TemplatesShow{Name: "../foo"}).Run,
+		(&TemplatesDelete{Name: "../foo"}).Run,
+	} {
+		_, err := NewTestingContext()._SetTemplate("../foo", "{{ TODAY }}\n")._Run(cmd)
+		require.Error(t, err)
+		assert.Equal(t, "Invalid template name", err.Error())
+	}
+}
+
+func TestDeleteTemplate(t *testing.T) {
+	ctx := NewTestingContext()._SetTemplate("daily", "{{ TODAY }}\n")
+	state, err := ctx._Run((&TemplatesDelete{Name: "daily"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\nRemoved template daily\n", state.printBuffer)
+	assert.Len(t, ctx.templates, 0)
+
+	_, err = ctx._Run((&TemplatesDelete{Name: "daily"}).Run)
+	require.Error(t, err)
+}
@@ -7,6 +7,11 @@ import (
 	"github.com/jotaen/klog/src/app/cli/lib"
 	"github.com/jotaen/klog/src/parser"
 	"github.com/jotaen/klog/src/parser/parsing"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
 	gotime "time"
 )
 
@@ -16,15 +21,52 @@ func NewTestingContext() TestingContext {
 		State: State{
 			printBuffer:         "",
 			writtenFileContents: "",
+			writtenFiles:        map[string]string{},
 		},
 		now:         gotime.Now(),
 		records:     nil,
 		parseResult: nil,
 		serialiser:  lib.NewCliSerialiser(),
 		bookmarks:   bc,
+		files:       map[app.FileOrBookmarkName]string{},
+		templates:   map[string]string{},
 	}
 }
 
+// newContextWithFailingGitCommit returns a real context, which is configured to
+// commit all written files to git. The returned folder is a git repository, in
+// which every commit fails.
+func newContextWithFailingGitCommit(t *testing.T) (app.Context, string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, k := range []string{"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(k, "klog")
+	}
+	writeFile := func(path string, contents string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(filepath.Join(home, ".klog", "config.json"), `{"git": {"auto_commit": true}}`)
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "-C", repo, "init", "--quiet").CombinedOutput(); err != nil {
+		t.Fatal(string(out))
+	}
+	writeFile(filepath.Join(repo, ".git", "hooks", "pre-commit"), "#!/bin/sh\nexit 1\n")
+	ctx, err := app.NewContext(home, lib.NewCliSerialiser())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx, repo
+}
+
 func (ctx TestingContext) _SetRecords(records string) TestingContext {
 	pr, err := parser.Parse(records)
 	if err != nil {
@@ -35,6 +77,51 @@ func (ctx TestingContext) _SetRecords(records string) TestingContext {
 	return ctx
 }
 
+// _SetArchivedRecords sets records that are only read if the archives are
+// included for their respective years.
+func (ctx TestingContext) _SetArchivedRecords(records string) TestingContext {
+	pr, err := parser.Parse(records)
+	if err != nil {
+		panic("Invalid records")
+	}
+	ctx.archivedRecords = pr.Records
+	return ctx
+}
+
+// _SetFile registers a file that can be read via its (absolute) path.
+func (ctx TestingContext) _SetFile(path string, contents string) TestingContext {
+	ctx.files[app.FileOrBookmarkName(path)] = contents
+	return ctx
+}
+
+func (ctx TestingContext) _SetConfig(config app.Config) TestingContext {
+	ctx.config = config
+	return ctx
+}
+
+// _SetConfigError makes reading the config fail.
+func (ctx TestingContext) _SetConfigError(err app.Error) TestingContext {
+	ctx.configError = err
+	return ctx
+}
+
+func (ctx TestingContext) _SetTemplate(name string, contents string) TestingContext {
+	ctx.templates[name] = contents
+	return ctx
+}
+
+// _SetInputLines sets the lines that the user enters when prompted.
+func (ctx TestingContext) _SetInputLines(lines ...string) TestingContext {
+	ctx.inputLines = lines
+	return ctx
+}
+
+// _SetStdin sets the text that is piped in via stdin.
+func (ctx TestingContext) _SetStdin(stdin string) TestingContext {
+	ctx.stdin = stdin
+	return ctx
+}
+
 func (ctx TestingContext) _SetNow(Y int, M int, D int, h int, m int) TestingContext {
 	ctx.now = gotime.Date(Y, gotime.Month(M), D, h, m, 0, 0, gotime.UTC)
 	return ctx
@@ -46,21 +133,31 @@ func (ctx TestingContext) _Run(cmd func(app.Context) error) (State, error) {
 	if len(out) > 0 && out[0] != '\n' {
 		out = "\n" + out
 	}
-	return State{out, ctx.writtenFileContents}, cmdErr
+	return State{out, ctx.writtenFileContents, ctx.writtenFiles, ctx.numberOfWrites}, cmdErr
 }
 
 type State struct {
 	printBuffer         string
 	writtenFileContents string
+	writtenFiles        map[string]string
+	numberOfWrites      int
 }
 
 type TestingContext struct {
 	State
-	now         gotime.Time
-	records     []Record
-	parseResult *parser.ParseResult
-	serialiser  *parser.Serialiser
-	bookmarks   app.BookmarksCollection
+	now                   gotime.Time
+	records               []Record
+	parseResult           *parser.ParseResult
+	archivedRecords       []Record
+	isArchiveYearIncluded func(int) bool
+	serialiser            *parser.Serialiser
+	bookmarks             app.BookmarksCollection
+	files                 map[app.FileOrBookmarkName]string
+	config                app.Config
+	configError           app.Error
+	templates             map[string]string
+	inputLines            []string
+	stdin                 string
 }
 
 func (ctx *TestingContext) Print(s string) {
@@ -68,7 +165,16 @@ func (ctx *TestingContext) Print(s string) {
 }
 
 func (ctx *TestingContext) ReadLine() (string, app.Error) {
-	return "", nil
+	if len(ctx.inputLines) == 0 {
+		return "", nil
+	}
+	line := ctx.inputLines[0]
+	ctx.inputLines = ctx.inputLines[1:]
+	return line, nil
+}
+
+func (ctx *TestingContext) TerminalWidth() int {
+	return 60
 }
 
 func (ctx *TestingContext) HomeFolder() string {
@@ -90,14 +196,67 @@ func (ctx *TestingContext) MetaInfo() struct {
 }
 
 func (ctx *TestingContext) ReadInputs(_ ...app.FileOrBookmarkName) ([]Record, error) {
-	return ctx.records, nil
+	if ctx.isArchiveYearIncluded == nil {
+		return ctx.records, nil
+	}
+	var records []Record
+	for _, r := range ctx.archivedRecords {
+		if ctx.isArchiveYearIncluded(r.Date().Year()) {
+			records = append(records, r)
+		}
+	}
+	return append(records, ctx.records...), nil
+}
+
+func (ctx *TestingContext) IncludeArchives(isYearIncluded func(int) bool) {
+	ctx.isArchiveYearIncluded = isYearIncluded
 }
 
-func (ctx *TestingContext) ReadFileInput(app.FileOrBookmarkName) (*parser.ParseResult, app.File, error) {
-	return ctx.parseResult, nil, nil
+func (ctx *TestingContext) ReadFileInput(fileArg app.FileOrBookmarkName) (*parser.ParseResult, app.File, error) {
+	if len(ctx.files) == 0 {
+		return ctx.parseResult, nil, nil
+	}
+	contents, ok := ctx.files[fileArg]
+	if !ok {
+		return nil, nil, app.NewErrorWithCode(app.NO_SUCH_FILE, "No such file", string(fileArg), nil)
+	}
+	pr, err := parser.Parse(contents)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pr, app.NewFileOrPanic(string(fileArg)), nil
 }
 
-func (ctx *TestingContext) WriteFile(_ app.File, contents string) app.Error {
+// ReadFileRevision reads the file that was registered as `revision:path`.
+func (ctx *TestingContext) ReadFileRevision(revision string, fileArg app.FileOrBookmarkName) (*parser.ParseResult, app.File, error) {
+	contents, ok := ctx.files[app.FileOrBookmarkName(revision+":"+string(fileArg))]
+	if !ok {
+		return nil, nil, app.NewErrorWithCode(app.NO_SUCH_FILE, "Cannot read git revision "+revision, string(fileArg), nil)
+	}
+	pr, err := parser.Parse(contents)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pr, app.NewFileOrPanic(string(fileArg)), nil
+}
+
+func (ctx *TestingContext) ReadStdinInput() (*parser.ParseResult, error) {
+	pr, err := parser.Parse(ctx.stdin)
+	if err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+func (ctx *TestingContext) WriteFile(target app.File, contents string) app.Error {
+	ctx.numberOfWrites++
+	if target != nil {
+		ctx.writtenFiles[target.Path()] = contents
+		if _, isKnownFile := ctx.files[app.FileOrBookmarkName(target.Path())]; isKnownFile {
+			ctx.files[app.FileOrBookmarkName(target.Path())] = contents
+		}
+		return nil
+	}
 	ctx.writtenFileContents = contents
 	return nil
 }
@@ -110,8 +269,15 @@ func (ctx *TestingContext) ReadBookmarks() (app.BookmarksCollection, app.Error)
 	return ctx.bookmarks, nil
 }
 
-func (ctx *TestingContext) ManipulateBookmarks(_ func(app.BookmarksCollection) app.Error) app.Error {
-	return nil
+func (ctx *TestingContext) ReadConfig(_ ...app.FileOrBookmarkName) (app.Config, app.Error) {
+	if ctx.configError != nil {
+		return app.Config{}, ctx.configError
+	}
+	return ctx.config, nil
+}
+
+func (ctx *TestingContext) ManipulateBookmarks(manipulate func(app.BookmarksCollection) app.Error) app.Error {
+	return manipulate(ctx.bookmarks)
 }
 
 func (ctx *TestingContext) OpenInFileBrowser(_ app.File) app.Error {
@@ -122,8 +288,42 @@ func (ctx *TestingContext) OpenInEditor(_ app.FileOrBookmarkName, _ func(string)
 	return nil
 }
 
-func (ctx *TestingContext) InstantiateTemplate(_ string) ([]parsing.Text, app.Error) {
-	return nil, nil
+func (ctx *TestingContext) InstantiateTemplate(templateName string, variables map[string]string) ([]parsing.Text, error) {
+	template, err := ctx.ReadTemplate(templateName)
+	if err != nil {
+		return nil, err
+	}
+	return parser.RenderTemplate(template, variables)
+}
+
+func (ctx *TestingContext) TemplateNames() []string {
+	var names []string
+	for n := range ctx.templates {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (ctx *TestingContext) ReadTemplate(templateName string) (string, app.Error) {
+	template, ok := ctx.templates[templateName]
+	if !ok {
+		return "", app.NewErrorWithCode(app.NO_SUCH_FILE, "No such template", templateName, nil)
+	}
+	return template, nil
+}
+
+func (ctx *TestingContext) WriteTemplate(templateName string, contents string) app.Error {
+	ctx.templates[templateName] = contents
+	return nil
+}
+
+func (ctx *TestingContext) DeleteTemplate(templateName string) app.Error {
+	if _, ok := ctx.templates[templateName]; !ok {
+		return app.NewErrorWithCode(app.NO_SUCH_FILE, "No such template", templateName, nil)
+	}
+	delete(ctx.templates, templateName)
+	return nil
 }
 
 func (ctx *TestingContext) Serialiser() *parser.Serialiser {
@@ -136,3 +336,5 @@ func (ctx *TestingContext) SetSerialiser(serialiser *parser.Serialiser) {
 	}
 	ctx.serialiser = serialiser
 }
+
+func (ctx *TestingContext) SetCommandName(string) {}
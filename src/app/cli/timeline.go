@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/jotaen/klog/lib/jotaen/terminalformat"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/service"
+	"strings"
+	gotime "time"
+)
+
+type Timeline struct {
+	lib.FilterArgs
+	lib.NoStyleArgs
+	lib.InputFilesArgs
+}
+
+func (opt *Timeline) Help() string {
+	return `Draws every record as a horizontal bar along the hours of the day, so that you can see when you worked.
+
+The time axis is extended if ranges reach into the previous or the next day (e.g. <23:00 or 1:30>).
+Open ranges are drawn up until now, and overlapping ranges are highlighted.
+Entries that are durations don’t have a position in time, so they are listed separately.`
+}
+
+type timelineCell struct {
+	char      string
+	plainChar string
+	color     string
+}
+
+var (
+	timelineEmpty   = timelineCell{"·", ".", "238"}
+	timelineRange   = timelineCell{"█", "#", "117"}
+	timelineOpen    = timelineCell{"█", "~", "027"}
+	timelineOverlap = timelineCell{"█", "X", "167"}
+)
+
+// The possible resolutions of the time axis, in minutes per cell.
+var timelineResolutions = []int{15, 20, 30, 60, 120}
+
+type timeInterval struct {
+	start int
+	end   int
+}
+
+func (opt *Timeline) Run(ctx app.Context) error {
+	opt.NoStyleArgs.Apply(&ctx)
+	opt.FilterArgs.Apply(&ctx)
+	records, err := ctx.ReadInputs(opt.File...)
+	if err != nil {
+		return err
+	}
+	now := ctx.Now()
+	records = service.Sort(opt.ApplyFilter(now, records), true)
+	if len(records) == 0 {
+		return nil
+	}
+
+	// Determine the positions of all entries, and the extent of the time axis.
+	ranges := make([][]timeInterval, len(records))
+	openRanges := make([][]timeInterval, len(records))
+	axisStart, axisEnd := 0, 24*60
+	for i, r := range records {
+		for _, e := range r.Entries() {
+			e.Unbox(
+				func(tr Range) interface{} {
+					ranges[i] = append(ranges[i], timeInterval{
+						tr.Start().MidnightOffset().InMinutes(),
+						tr.End().MidnightOffset().InMinutes(),
+					})
+					return nil
+				},
+				func(Duration) interface{} { return nil },
+				func(or OpenRange) interface{} {
+					start := or.Start().MidnightOffset().InMinutes()
+					end := openRangeEnd(r.Date(), now)
+					if end <= start {
+						end = start + 1 // Only mark the start
+					}
+					openRanges[i] = append(openRanges[i], timeInterval{start, end})
+					return nil
+				},
+			)
+		}
+		for _, is := range [][]timeInterval{ranges[i], openRanges[i]} {
+			for _, interval := range is {
+				if interval.start < axisStart {
+					axisStart = (interval.start - 59) / 60 * 60
+				}
+				if interval.end > axisEnd {
+					axisEnd = (interval.end + 59) / 60 * 60
+				}
+			}
+		}
+	}
+
+	// Choose the finest resolution that fits into the terminal.
+	prefixWidth := len("Mon 2006-01-02 ")
+	resolution := timelineResolutions[len(timelineResolutions)-1]
+	for _, res := range timelineResolutions {
+		if prefixWidth+(axisEnd-axisStart)/res+10 <= ctx.TerminalWidth() {
+			resolution = res
+			break
+		}
+	}
+	// The axis is extended to a full cell, so that the last slot is not cut off.
+	if remainder := (axisEnd - axisStart) % resolution; remainder != 0 {
+		axisEnd += resolution - remainder
+	}
+	numberOfCells := (axisEnd - axisStart) / resolution
+	format := func(c timelineCell) string {
+		if opt.IsStyled() {
+			return terminalformat.Style{Color: c.color}.Format(c.char)
+		}
+		return c.plainChar
+	}
+
+	// Hour labels
+	labels := []rune(strings.Repeat(" ", prefixWidth+numberOfCells))
+	labelInterval := 60
+	for labelInterval/resolution < 3 {
+		labelInterval += 60
+	}
+	for m := axisStart; m < axisEnd; m += labelInterval {
+		hour := ((m/60)%24 + 24) % 24
+		copy(labels[prefixWidth+(m-axisStart)/resolution:], []rune(fmt.Sprint(hour)))
+	}
+	ctx.Print(strings.TrimRight(string(labels), " ") + "\n")
+
+	// Bars
+	var durations []string
+	for i, r := range records {
+		overlaps := findOverlaps(append(append([]timeInterval{}, ranges[i]...), openRanges[i]...))
+		line := lib.PrettyDay(r.Date().Weekday())[:3] + " " + ctx.Serialiser().Date(r.Date()) + " "
+		for c := 0; c < numberOfCells; c++ {
+			cell := timeInterval{axisStart + c*resolution, axisStart + (c+1)*resolution}
+			switch {
+			case intersectsAny(cell, overlaps):
+				line += format(timelineOverlap)
+			case intersectsAny(cell, ranges[i]):
+				line += format(timelineRange)
+			case intersectsAny(cell, openRanges[i]):
+				line += format(timelineOpen)
+			default:
+				line += format(timelineEmpty)
+			}
+		}
+		total, _ := service.HypotheticalTotal(now, r)
+		ctx.Print(line + " " + ctx.Serialiser().Duration(total) + "\n")
+
+		for _, e := range r.Entries() {
+			e.Unbox(
+				func(Range) interface{} { return nil },
+				func(d Duration) interface{} {
+					text := ctx.Serialiser().Date(r.Date()) + " " + ctx.Serialiser().Duration(d)
+					if e.Summary() != "" {
+						text += " " + ctx.Serialiser().Summary(e.Summary())
+					}
+					durations = append(durations, text)
+					return nil
+				},
+				func(OpenRange) interface{} { return nil },
+			)
+		}
+	}
+
+	// Durations
+	if len(durations) > 0 {
+		ctx.Print("\nDurations (without time of day):\n")
+		for _, d := range durations {
+			ctx.Print(d + "\n")
+		}
+	}
+	return nil
+}
+
+// openRangeEnd returns the current time in minutes relative to the start of
+// the given date, or -1 if now isn’t at or right after that date.
+func openRangeEnd(date Date, now gotime.Time) int {
+	end := NewTimeFromTime(now).MidnightOffset().InMinutes()
+	today := NewDateFromTime(now)
+	if date.IsEqualTo(today) {
+		return end
+	}
+	if date.PlusDays(1).IsEqualTo(today) {
+		return end + 24*60
+	}
+	return -1
+}
+
+// findOverlaps returns the intervals where at least two of the given
+// intervals overlap.
+func findOverlaps(is []timeInterval) []timeInterval {
+	var overlaps []timeInterval
+	for i, a := range is {
+		for _, b := range is[i+1:] {
+			start, end := a.start, a.end
+			if b.start > start {
+				start = b.start
+			}
+			if b.end < end {
+				end = b.end
+			}
+			if start < end {
+				overlaps = append(overlaps, timeInterval{start, end})
+			}
+		}
+	}
+	return overlaps
+}
+
+func intersectsAny(cell timeInterval, is []timeInterval) bool {
+	for _, i := range is {
+		if i.start < cell.end && i.end > cell.start {
+			return true
+		}
+	}
+	return false
+}
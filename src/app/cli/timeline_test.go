@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTimelineOfEmptyInput(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(``)._Run((&Timeline{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "", state.printBuffer)
+}
+
+func TestPrintTimeline(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2021-03-01
+	8:00 - 12:00
+	11:00 - 14:00 Overlap
+	1h Lunch break
+
+2021-03-02
+	<22:00 - 2:00
+	1h30m #meeting
+`)._Run((&Timeline{NoStyleArgs: lib.NoStyleArgs{NoStyle: true}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+               22 1  4  7  10 13 16 19 22
+Mon 2021-03-01 ..........###X##.......... 8h
+Tue 2021-03-02 ####...................... 5h30m
+
+Durations (without time of day):
+2021-03-01 1h Lunch break
+2021-03-02 1h30m #meeting
+`, state.printBuffer)
+}
+
+func TestPrintTimelineWithOpenRange(t *testing.T) {
+	state, err := NewTestingContext()._SetNow(2021, 3, 2, 1, 0)._SetRecords(`
+2021-03-01
+	20:00 - ?
+`)._Run((&Timeline{NoStyleArgs: lib.NoStyleArgs{NoStyle: true}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+               0  3  6  9  12 15 18 21 0
+Mon 2021-03-01 ....................~~~~~ 5h
+`, state.printBuffer)
+}
+
+func TestPrintTimelineWithPartialLastCell(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2021-03-01
+	<6:00 - 1:00>
+`)._Run((&Timeline{NoStyleArgs: lib.NoStyleArgs{NoStyle: true}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+               6  12 18 0  6  12 18 0
+Mon 2021-03-01 ###################### 43h
+`, state.printBuffer)
+}
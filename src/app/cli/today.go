@@ -57,13 +57,30 @@ func handle(opt *Today, ctx app.Context) error {
 		return err
 	}
 
+	config, err := opt.DiffArgs.Config(ctx, opt.File...)
+	if err != nil {
+		return err
+	}
+	schedule := config.Schedule
+
 	currentRecords, otherRecords, isYesterday := splitIntoCurrentAndOther(now, records)
 	hasCurrentRecords := len(currentRecords) > 0
 
-	currentTotal, currentShouldTotal, currentDiff := opt.evaluate(now, currentRecords)
+	currentTotal, currentShouldTotal, currentDiff := opt.evaluate(now, schedule, currentRecords)
 	currentEndTime, _ := NewTimeFromTime(now).Add(NewDuration(0, 0).Minus(currentDiff))
 
-	otherTotal, otherShouldTotal, otherDiff := opt.evaluate(now, otherRecords)
+	otherTotal, otherShouldTotal, otherDiff := opt.evaluate(now, schedule, otherRecords)
+	if len(records) > 0 {
+		// Days without any record count as owed time, if the schedule requires it.
+		currentDate := NewDateFromTime(now)
+		if isYesterday {
+			currentDate = currentDate.PlusDays(-1)
+		}
+		first := service.Sort(records, true)[0].Date()
+		unrecorded := service.UnrecordedShouldTotal(schedule, first, currentDate.PlusDays(-1), records...)
+		otherShouldTotal = NewShouldTotal(0, otherShouldTotal.Plus(unrecorded).InMinutes())
+		otherDiff = otherDiff.Minus(unrecorded)
+	}
 
 	grandTotal := currentTotal.Plus(otherTotal)
 	grandShouldTotal := NewShouldTotal(0, currentShouldTotal.Plus(otherShouldTotal).InMinutes())
@@ -168,15 +185,15 @@ func handle(opt *Today, ctx app.Context) error {
 	return nil
 }
 
-func (opt *Today) evaluate(now gotime.Time, records []Record) (Duration, Duration, Duration) {
+func (opt *Today) evaluate(now gotime.Time, schedule *service.Schedule, records []Record) (Duration, Duration, Duration) {
 	total, _ := func() (Duration, bool) {
 		if opt.Now {
 			return service.HypotheticalTotal(now, records...)
 		}
 		return service.Total(records...), false
 	}()
-	shouldTotal := service.ShouldTotalSum(records...)
-	diff := service.Diff(shouldTotal, total)
+	shouldTotal := service.ShouldTotalSum(schedule, records...)
+	diff := service.Diff(shouldTotal, total.Plus(service.CreditSum(schedule, records...)))
 	return total, shouldTotal, diff
 }
 
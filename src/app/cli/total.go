@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	. "github.com/jotaen/klog/src"
 	"github.com/jotaen/klog/src/app"
 	"github.com/jotaen/klog/src/app/cli/lib"
 	"github.com/jotaen/klog/src/service"
@@ -26,17 +27,34 @@ which treats all open-ended time ranges as if they were closed right now.`
 
 func (opt *Total) Run(ctx app.Context) error {
 	opt.NoStyleArgs.Apply(&ctx)
+	opt.FilterArgs.Apply(&ctx)
 	records, err := ctx.ReadInputs(opt.File...)
 	if err != nil {
 		return err
 	}
 	now := ctx.Now()
+	allRecords := records
 	records = opt.ApplyFilter(now, records)
 	total := opt.NowArgs.Total(now, records...)
 	ctx.Print(fmt.Sprintf("Total: %s\n", ctx.Serialiser().Duration(total)))
 	if opt.Diff {
-		should := service.ShouldTotalSum(records...)
-		diff := service.Diff(should, total)
+		config, err := opt.DiffArgs.Config(ctx, opt.File...)
+		if err != nil {
+			return err
+		}
+		schedule := config.Schedule
+		should := service.ShouldTotalSum(schedule, records...)
+		if len(records) > 0 {
+			sorted := service.Sort(records, true)
+			// Records that were filtered out still count as recorded days.
+			unrecorded := service.UnrecordedShouldTotal(schedule, sorted[0].Date(), sorted[len(sorted)-1].Date(), allRecords...)
+			should = NewShouldTotal(0, should.Plus(unrecorded).InMinutes())
+		}
+		credit := service.CreditSum(schedule, records...)
+		diff := service.Diff(should, total.Plus(credit))
+		if credit.InMinutes() != 0 {
+			ctx.Print(fmt.Sprintf("Credit: %s\n", ctx.Serialiser().Duration(credit)))
+		}
 		ctx.Print(fmt.Sprintf("Should: %s\n", ctx.Serialiser().ShouldTotal(should)))
 		ctx.Print(fmt.Sprintf("Diff: %s\n", ctx.Serialiser().SignedDuration(diff)))
 	}
@@ -1,7 +1,10 @@
 package cli
 
 import (
+	"github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/app"
 	"github.com/jotaen/klog/src/app/cli/lib"
+	"github.com/jotaen/klog/src/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -40,3 +43,40 @@ func TestTotalWithDiffing(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, "\nTotal: 16h30m\nShould: 15h45m!\nDiff: +45m\n(In 2 records)\n", state.printBuffer)
 }
+
+func TestTotalWithDiffingFromSchedule(t *testing.T) {
+	state, err := NewTestingContext()._SetConfig(app.Config{
+		Schedule: &service.Schedule{Periods: []service.SchedulePeriod{{
+			Since: klog.Ɀ_Date_(2018, 1, 1),
+			Hours: map[int]klog.Duration{4: klog.NewDuration(8, 0), 5: klog.NewDuration(6, 0)},
+		}}},
+	})._SetRecords(`
+2018-11-08
+	8h30m
+
+2018-11-15 (1h!)
+	1h
+`)._Run((&Total{DiffArgs: lib.DiffArgs{Diff: true}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\nTotal: 9h30m\nShould: 15h!\nDiff: -5h30m\n(In 2 records)\n", state.printBuffer)
+}
+
+func TestTotalWithDiffingFromScheduleAndTagFilter(t *testing.T) {
+	state, err := NewTestingContext()._SetConfig(app.Config{
+		Schedule: &service.Schedule{Periods: []service.SchedulePeriod{{
+			Since: klog.Ɀ_Date_(2018, 1, 1),
+			Hours: map[int]klog.Duration{1: klog.NewDuration(8, 0), 2: klog.NewDuration(8, 0), 3: klog.NewDuration(8, 0)},
+		}}},
+	})._SetRecords(`
+2018-07-09
+	8h #a
+
+2018-07-10
+	8h #b
+
+2018-07-11
+	7h #a
+`)._Run((&Total{DiffArgs: lib.DiffArgs{Diff: true}, FilterArgs: lib.FilterArgs{Tags: []string{"a"}}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\nTotal: 15h\nShould: 16h!\nDiff: -1h\n(In 2 records)\n", state.printBuffer)
+}
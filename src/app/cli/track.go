@@ -22,7 +22,10 @@ func (opt *Track) Help() string {
 Example: klog track '1h work' file.klg
 
 Remember to use 'quotes' if the entry consists of multiple words,
-and to avoid the text being processed by your shell.`
+and to avoid the text being processed by your shell.
+
+With - as file, the records are read from stdin, and the result is printed to stdout.
+That way, klog can be used as filter, e.g. in vim: :%!klog track '1h work' -`
 }
 
 func (opt *Track) Run(ctx app.Context) error {
@@ -41,3 +41,35 @@ func TestTrackEntryAtUnknownDateCreatesNewRecord(t *testing.T) {
 	2h
 `, state.writtenFileContents)
 }
+
+func TestTrackEntryViaStdin(t *testing.T) {
+	state, err := NewTestingContext()._SetStdin(`
+1855-04-25
+	1h
+`)._Run((&Track{
+		Entry:          "2h",
+		AtDateArgs:     lib.AtDateArgs{Date: klog.Ɀ_Date_(1855, 4, 25)},
+		OutputFileArgs: lib.OutputFileArgs{File: lib.STDIO},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+1855-04-25
+	1h
+	2h
+`, state.printBuffer)
+	assert.Equal(t, "", state.writtenFileContents)
+	assert.Len(t, state.writtenFiles, 0)
+}
+
+func TestTrackEntryViaEmptyStdinCreatesNewRecord(t *testing.T) {
+	state, err := NewTestingContext()._SetStdin("")._Run((&Track{
+		Entry:          "2h",
+		AtDateArgs:     lib.AtDateArgs{Date: klog.Ɀ_Date_(1855, 4, 25)},
+		OutputFileArgs: lib.OutputFileArgs{File: lib.STDIO},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+1855-04-25
+    2h
+`, state.printBuffer)
+}
@@ -0,0 +1,263 @@
+package app
+
+import (
+	"encoding/json"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/service"
+	"strings"
+)
+
+// Config contains the user’s settings, which are stored in the
+// `config.json` file in the klog folder.
+type Config struct {
+	Schedule *service.Schedule
+	Balance  *service.BalanceRules
+
+	// Calendars are the paths of holiday calendar files, which apply to
+	// all inputs. BookmarkCalendars only apply to the respective bookmark.
+	Calendars         []string
+	BookmarkCalendars map[Name][]string
+
+	// Git are the settings for committing changed files to git. BookmarkGit
+	// takes precedence for the files of the respective bookmark.
+	Git         GitSettings
+	BookmarkGit map[Name]GitSettings
+}
+
+type GitSettings struct {
+	AutoCommit bool
+	AutoPush   bool
+}
+
+type configJson struct {
+	Schedule  *scheduleJson                 `json:"schedule"`
+	Balance   *balanceJson                  `json:"balance"`
+	Bookmarks map[string]bookmarkConfigJson `json:"bookmarks"`
+	Git       *gitJson                      `json:"git"`
+}
+
+type scheduleJson struct {
+	Periods   []schedulePeriodJson `json:"periods"`
+	Holidays  []string             `json:"holidays"`
+	Calendars []string             `json:"calendars"`
+	Vacation  []string             `json:"vacation"`
+	Leave     []struct {
+		Type  string `json:"type"`
+		From  string `json:"from"`
+		Until string `json:"until"`
+	} `json:"leave"`
+	Quotas map[string]int `json:"quotas"`
+}
+
+type bookmarkConfigJson struct {
+	Calendars []string `json:"calendars"`
+	Git       *gitJson `json:"git"`
+}
+
+type gitJson struct {
+	AutoCommit bool `json:"auto_commit"`
+	AutoPush   bool `json:"auto_push"`
+}
+
+func (g gitJson) toSettings() GitSettings {
+	return GitSettings{AutoCommit: g.AutoCommit, AutoPush: g.AutoPush}
+}
+
+type schedulePeriodJson struct {
+	Since *string           `json:"since"`
+	Hours map[string]string `json:"hours"`
+}
+
+type balanceJson struct {
+	Initial   *string `json:"initial"`
+	CarryOver *struct {
+		Period string  `json:"period"`
+		Max    *string `json:"max"`
+		Reset  bool    `json:"reset"`
+	} `json:"carry_over"`
+	Corrections []struct {
+		Date  string `json:"date"`
+		Value string `json:"value"`
+		Note  string `json:"note"`
+	} `json:"corrections"`
+}
+
+var weekdays = map[string]int{"mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6, "sun": 7}
+
+var balancePeriods = map[string]service.BalancePeriod{
+	"month":   service.MonthlyBalancePeriod,
+	"quarter": service.QuarterlyBalancePeriod,
+	"year":    service.YearlyBalancePeriod,
+}
+
+func newConfigError(details string, err error) Error {
+	return NewErrorWithCode(
+		CONFIG_ERROR,
+		"Invalid configuration",
+		details,
+		err,
+	)
+}
+
+func NewConfigFromJson(jsonText string) (Config, Error) {
+	config := Config{}
+	if strings.TrimSpace(jsonText) == "" {
+		return config, nil
+	}
+	var raw configJson
+	err := json.Unmarshal([]byte(jsonText), &raw)
+	if err != nil {
+		return Config{}, newConfigError("The JSON in your config file is malformed", err)
+	}
+	if raw.Schedule != nil {
+		schedule, sErr := parseSchedule(raw.Schedule)
+		if sErr != nil {
+			return Config{}, sErr
+		}
+		config.Schedule = schedule
+		config.Calendars = raw.Schedule.Calendars
+	}
+	if raw.Git != nil {
+		config.Git = raw.Git.toSettings()
+	}
+	for name, b := range raw.Bookmarks {
+		if b.Git != nil {
+			if config.BookmarkGit == nil {
+				config.BookmarkGit = make(map[Name]GitSettings)
+			}
+			config.BookmarkGit[NewName(name)] = b.Git.toSettings()
+		}
+		if len(b.Calendars) == 0 {
+			continue
+		}
+		if config.BookmarkCalendars == nil {
+			config.BookmarkCalendars = make(map[Name][]string)
+		}
+		config.BookmarkCalendars[NewName(name)] = b.Calendars
+	}
+	if raw.Balance != nil {
+		balance, bErr := parseBalance(raw.Balance)
+		if bErr != nil {
+			return Config{}, bErr
+		}
+		config.Balance = balance
+	}
+	return config, nil
+}
+
+func parseSchedule(raw *scheduleJson) (*service.Schedule, Error) {
+	schedule := &service.Schedule{}
+	for _, p := range raw.Periods {
+		if p.Since == nil {
+			return nil, newConfigError("Every schedule period must have a `since` date", nil)
+		}
+		since, dErr := NewDateFromString(*p.Since)
+		if dErr != nil {
+			return nil, newConfigError("Invalid date in schedule: "+*p.Since, dErr)
+		}
+		period := service.SchedulePeriod{Since: since, Hours: make(map[int]Duration)}
+		for day, value := range p.Hours {
+			weekday, ok := weekdays[strings.ToLower(day)]
+			if !ok {
+				return nil, newConfigError("Invalid weekday in schedule: "+day, nil)
+			}
+			hours, hErr := NewDurationFromString(value)
+			if hErr != nil {
+				return nil, newConfigError("Invalid duration in schedule: "+value, hErr)
+			}
+			period.Hours[weekday] = hours
+		}
+		schedule.Periods = append(schedule.Periods, period)
+	}
+	for _, value := range raw.Holidays {
+		d, dErr := NewDateFromString(value)
+		if dErr != nil {
+			return nil, newConfigError("Invalid date in schedule: "+value, dErr)
+		}
+		schedule.Holidays = append(schedule.Holidays, service.Holiday{Date: d})
+	}
+	for _, value := range raw.Vacation {
+		d, dErr := NewDateFromString(value)
+		if dErr != nil {
+			return nil, newConfigError("Invalid date in schedule: "+value, dErr)
+		}
+		schedule.Vacation = append(schedule.Vacation, d)
+	}
+	for _, l := range raw.Leave {
+		dayType, tErr := parseDayType(l.Type)
+		if tErr != nil {
+			return nil, tErr
+		}
+		from, fErr := NewDateFromString(l.From)
+		if fErr != nil {
+			return nil, newConfigError("Invalid date in schedule: "+l.From, fErr)
+		}
+		until, uErr := NewDateFromString(l.Until)
+		if uErr != nil {
+			return nil, newConfigError("Invalid date in schedule: "+l.Until, uErr)
+		}
+		schedule.Leave = append(schedule.Leave, service.Leave{Type: dayType, From: from, Until: until})
+	}
+	for name, quota := range raw.Quotas {
+		dayType, tErr := parseDayType(name)
+		if tErr != nil {
+			return nil, tErr
+		}
+		if schedule.Quotas == nil {
+			schedule.Quotas = make(map[service.DayType]int)
+		}
+		schedule.Quotas[dayType] = quota
+	}
+	return schedule, nil
+}
+
+func parseDayType(value string) (service.DayType, Error) {
+	for _, t := range service.DayTypes {
+		if string(t) == strings.ToLower(value) {
+			return t, nil
+		}
+	}
+	return service.RegularDay, newConfigError("Invalid day type (must be vacation, sick or holiday): "+value, nil)
+}
+
+func parseBalance(raw *balanceJson) (*service.BalanceRules, Error) {
+	rules := &service.BalanceRules{}
+	if raw.Initial != nil {
+		initial, dErr := NewDurationFromString(*raw.Initial)
+		if dErr != nil {
+			return nil, newConfigError("Invalid initial balance: "+*raw.Initial, dErr)
+		}
+		rules.Initial = initial
+	}
+	if raw.CarryOver != nil {
+		period, ok := balancePeriods[strings.ToLower(raw.CarryOver.Period)]
+		if !ok {
+			return nil, newConfigError("Invalid carry-over period (must be month, quarter or year): "+raw.CarryOver.Period, nil)
+		}
+		rules.Period = period
+		rules.Reset = raw.CarryOver.Reset
+		if raw.CarryOver.Max != nil {
+			max, dErr := NewDurationFromString(*raw.CarryOver.Max)
+			if dErr != nil {
+				return nil, newConfigError("Invalid maximum carry-over: "+*raw.CarryOver.Max, dErr)
+			}
+			rules.MaxCarryOver = max
+		}
+	}
+	for _, c := range raw.Corrections {
+		d, dErr := NewDateFromString(c.Date)
+		if dErr != nil {
+			return nil, newConfigError("Invalid date in balance correction: "+c.Date, dErr)
+		}
+		value, vErr := NewDurationFromString(c.Value)
+		if vErr != nil {
+			return nil, newConfigError("Invalid value in balance correction: "+c.Value, vErr)
+		}
+		rules.Corrections = append(rules.Corrections, service.BalanceCorrection{
+			Date:  d,
+			Value: value,
+			Note:  c.Note,
+		})
+	}
+	return rules, nil
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParsesEmptyConfig(t *testing.T) {
+	for _, text := range []string{"", "{}"} {
+		config, err := NewConfigFromJson(text)
+		require.Nil(t, err)
+		assert.Nil(t, config.Schedule)
+	}
+}
+
+func TestParsesSchedule(t *testing.T) {
+	config, err := NewConfigFromJson(`{
+  "schedule": {
+    "periods": [
+      {"since": "2020-01-01", "hours": {"mon": "8h", "fri": "4h30m"}}
+    ],
+    "holidays": ["2020-12-25"],
+    "vacation": ["2020-08-03", "2020-08-04"],
+    "leave": [{"type": "sick", "from": "2020-02-10", "until": "2020-02-12"}],
+    "quotas": {"vacation": 30}
+  }
+}`)
+	require.Nil(t, err)
+	require.NotNil(t, config.Schedule)
+	require.Len(t, config.Schedule.Periods, 1)
+	assert.Equal(t, Ɀ_Date_(2020, 1, 1), config.Schedule.Periods[0].Since)
+	assert.Equal(t, map[int]Duration{1: NewDuration(8, 0), 5: NewDuration(4, 30)}, config.Schedule.Periods[0].Hours)
+	assert.Equal(t, []service.Holiday{{Date: Ɀ_Date_(2020, 12, 25)}}, config.Schedule.Holidays)
+	assert.Len(t, config.Schedule.Vacation, 2)
+	assert.Equal(t, []service.Leave{
+		{Type: service.SickLeave, From: Ɀ_Date_(2020, 2, 10), Until: Ɀ_Date_(2020, 2, 12)},
+	}, config.Schedule.Leave)
+	assert.Equal(t, map[service.DayType]int{service.Vacation: 30}, config.Schedule.Quotas)
+}
+
+func TestParsesCalendars(t *testing.T) {
+	config, err := NewConfigFromJson(`{
+  "schedule": {"calendars": ["/holidays.ics"]},
+  "bookmarks": {"@work": {"calendars": ["/work.json"]}, "side": {}}
+}`)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"/holidays.ics"}, config.Calendars)
+	assert.Equal(t, map[Name][]string{"work": {"/work.json"}}, config.BookmarkCalendars)
+}
+
+func TestParsesBalance(t *testing.T) {
+	config, err := NewConfigFromJson(`{
+  "balance": {
+    "initial": "-2h30m",
+    "carry_over": {"period": "quarter", "max": "40h"},
+    "corrections": [{"date": "2020-06-30", "value": "-10h", "note": "Payout"}]
+  }
+}`)
+	require.Nil(t, err)
+	require.NotNil(t, config.Balance)
+	assert.Equal(t, NewDuration(-2, -30), config.Balance.Initial)
+	assert.Equal(t, service.QuarterlyBalancePeriod, config.Balance.Period)
+	assert.Equal(t, NewDuration(40, 0), config.Balance.MaxCarryOver)
+	assert.False(t, config.Balance.Reset)
+	assert.Equal(t, []service.BalanceCorrection{
+		{Date: Ɀ_Date_(2020, 6, 30), Value: NewDuration(-10, 0), Note: "Payout"},
+	}, config.Balance.Corrections)
+}
+
+func TestRejectsInvalidConfig(t *testing.T) {
+	for _, text := range []string{
+		`{`,
+		`{"schedule": {"periods": [{"hours": {"mon": "8h"}}]}}`,
+		`{"schedule": {"periods": [{"since": "2020-01-01", "hours": {"monday": "8h"}}]}}`,
+		`{"schedule": {"periods": [{"since": "2020-01-01", "hours": {"mon": "8"}}]}}`,
+		`{"schedule": {"holidays": ["2020-13-01"]}}`,
+		`{"schedule": {"leave": [{"type": "party", "from": "2020-01-01", "until": "2020-01-02"}]}}`,
+		`{"schedule": {"quotas": {"party": 3}}}`,
+		`{"balance": {"initial": "asdf"}}`,
+		`{"balance": {"carry_over": {"period": "week"}}}`,
+		`{"balance": {"corrections": [{"date": "2020-01-01", "value": "1"}]}}`,
+	} {
+		_, err := NewConfigFromJson(text)
+		require.Error(t, err, text)
+		assert.Equal(t, CONFIG_ERROR, err.Code())
+	}
+}
@@ -6,9 +6,12 @@ import (
 	. "github.com/jotaen/klog/src"
 	"github.com/jotaen/klog/src/parser"
 	"github.com/jotaen/klog/src/parser/parsing"
+	"github.com/jotaen/klog/src/service"
 	"os"
 	"os/exec"
 	"os/user"
+	"path/filepath"
+	"strconv"
 	"strings"
 	gotime "time"
 )
@@ -16,11 +19,14 @@ import (
 var BinaryVersion string   // will be set during build
 var BinaryBuildHash string // will be set during build
 
+const TEMPLATE_FILE_SUFFIX = ".template.klg"
+
 type FileOrBookmarkName string
 
 type Context interface {
 	Print(string)
 	ReadLine() (string, Error)
+	TerminalWidth() int
 	KlogFolder() string
 	HomeFolder() string
 	MetaInfo() struct {
@@ -28,27 +34,39 @@ type Context interface {
 		BuildHash string
 	}
 	ReadInputs(...FileOrBookmarkName) ([]Record, error)
+	IncludeArchives(func(year int) bool)
 	ReadFileInput(FileOrBookmarkName) (*parser.ParseResult, File, error)
+	ReadFileRevision(string, FileOrBookmarkName) (*parser.ParseResult, File, error)
+	ReadStdinInput() (*parser.ParseResult, error)
 	WriteFile(File, string) Error
 	Now() gotime.Time
 	ReadBookmarks() (BookmarksCollection, Error)
+	ReadConfig(...FileOrBookmarkName) (Config, Error)
 	ManipulateBookmarks(func(BookmarksCollection) Error) Error
 	OpenInFileBrowser(File) Error
 	OpenInEditor(FileOrBookmarkName, func(string)) Error
-	InstantiateTemplate(string) ([]parsing.Text, Error)
+	InstantiateTemplate(string, map[string]string) ([]parsing.Text, error)
+	TemplateNames() []string
+	ReadTemplate(string) (string, Error)
+	WriteTemplate(string, string) Error
+	DeleteTemplate(string) Error
 	Serialiser() *parser.Serialiser
 	SetSerialiser(*parser.Serialiser)
+	SetCommandName(string)
 }
 
 type context struct {
-	homeDir    string
-	serialiser *parser.Serialiser
+	homeDir               string
+	serialiser            *parser.Serialiser
+	isArchiveYearIncluded func(int) bool
+	commandName           string
 }
 
 func NewContext(homeDir string, serialiser *parser.Serialiser) (Context, error) {
 	return &context{
-		homeDir:    homeDir,
-		serialiser: serialiser,
+		homeDir:               homeDir,
+		serialiser:            serialiser,
+		isArchiveYearIncluded: nil,
 	}, nil
 }
 
@@ -82,6 +100,18 @@ func (ctx *context) HomeFolder() string {
 	return ctx.homeDir
 }
 
+// TerminalWidth returns the number of columns of the terminal, or a
+// reasonable default if that cannot be determined.
+func (ctx *context) TerminalWidth() int {
+	if width := terminalWidth(); width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 80
+}
+
 func (ctx *context) KlogFolder() string {
 	return ctx.homeDir + "/.klog/"
 }
@@ -135,17 +165,33 @@ func (ctx *context) ReadInputs(fileArgs ...FileOrBookmarkName) ([]Record, error)
 			nil,
 		)
 	}
+	if ctx.isArchiveYearIncluded != nil {
+		archives := findArchives(bc, files, ctx.isArchiveYearIncluded)
+		if len(archives) > 0 {
+			archiveFiles, aErr := (&fileRetriever{ReadFile, bc}).Retrieve(archives...)
+			if aErr != nil {
+				return nil, aErr
+			}
+			files = append(files, archiveFiles...)
+		}
+	}
 	var records []Record
 	for _, f := range files {
 		pr, parserErrors := parser.Parse(f.content)
 		if parserErrors != nil {
-			return nil, parserErrors
+			return nil, NewFileErrors(f.File, parserErrors)
 		}
 		records = append(records, pr.Records...)
 	}
 	return records, nil
 }
 
+// IncludeArchives makes `ReadInputs` also read the bookmarked archives of
+// the input files, for all years that the given function includes.
+func (ctx *context) IncludeArchives(isYearIncluded func(year int) bool) {
+	ctx.isArchiveYearIncluded = isYearIncluded
+}
+
 func (ctx *context) retrieveTargetFile(fileArg FileOrBookmarkName) (*fileWithContent, Error) {
 	bc, err := ctx.ReadBookmarks()
 	if err != nil {
@@ -173,16 +219,57 @@ func (ctx *context) ReadFileInput(fileArg FileOrBookmarkName) (*parser.ParseResu
 	}
 	pr, parserErrors := parser.Parse(target.content)
 	if parserErrors != nil {
-		return nil, nil, parserErrors
+		return nil, nil, NewFileErrors(target.File, parserErrors)
 	}
 	return pr, target, nil
 }
 
+// ReadStdinInput reads the records that are piped in via stdin.
+func (ctx *context) ReadStdinInput() (*parser.ParseResult, error) {
+	stdin, err := ReadStdin()
+	if err != nil {
+		return nil, err
+	}
+	pr, parserErrors := parser.Parse(stdin)
+	if parserErrors != nil {
+		return nil, NewFileErrors(nil, parserErrors)
+	}
+	return pr, nil
+}
+
+// WriteFile writes the contents to the file. If configured, it also commits
+// the file to git afterwards. Since the file has been written at that point,
+// a failing commit only results in a warning, not in an error.
 func (ctx *context) WriteFile(target File, contents string) Error {
 	if target == nil {
 		panic("No path specified")
 	}
-	return WriteToFile(target, contents)
+	originalContents, _ := ReadFile(target)
+	err := WriteToFile(target, contents)
+	if err != nil {
+		return err
+	}
+	if originalContents == contents {
+		return nil
+	}
+	config, cErr := ctx.readConfigFile()
+	bc, bErr := ctx.ReadBookmarks()
+	for _, e := range []Error{cErr, bErr} {
+		if e != nil {
+			ctx.Print("Warning: Cannot determine whether to commit the file to git. " +
+				"The file itself was saved successfully. " + e.Error() + ": " + e.Details() + "\n")
+			return nil
+		}
+	}
+	settings := config.gitSettingsFor(bc, target)
+	if !settings.AutoCommit {
+		return nil
+	}
+	message := commitMessage(ctx.commandName, originalContents, contents)
+	if gErr := ctx.commitToGit(target, message, settings.AutoPush); gErr != nil {
+		ctx.Print("Warning: " + gErr.Error() + ". " + gErr.Details() + "\n")
+	}
+	return nil
 }
 
 func (ctx *context) Now() gotime.Time {
@@ -227,6 +314,48 @@ func (ctx *context) ReadBookmarks() (BookmarksCollection, Error) {
 	return NewBookmarksCollectionFromJson(bookmarksDatabase)
 }
 
+// ReadConfig reads the user’s configuration. The holidays from the
+// calendars that apply to the given inputs are added to the schedule.
+func (ctx *context) ReadConfig(fileArgs ...FileOrBookmarkName) (Config, Error) {
+	config, cErr := ctx.readConfigFile()
+	if cErr != nil {
+		return Config{}, cErr
+	}
+	bc, bErr := ctx.ReadBookmarks()
+	if bErr != nil {
+		return Config{}, bErr
+	}
+	for _, path := range config.calendarsFor(bc, fileArgs) {
+		if !IsAbs(path) {
+			path = ctx.KlogFolder() + path
+		}
+		calendar, rErr := ReadFile(NewFileOrPanic(path))
+		if rErr != nil {
+			return Config{}, rErr
+		}
+		holidays, hErr := NewHolidaysFromCalendar(path, calendar)
+		if hErr != nil {
+			return Config{}, hErr
+		}
+		if config.Schedule == nil {
+			config.Schedule = &service.Schedule{}
+		}
+		config.Schedule.Holidays = append(config.Schedule.Holidays, holidays...)
+	}
+	return config, nil
+}
+
+func (ctx *context) readConfigFile() (Config, Error) {
+	configFile, err := ReadFile(ctx.configPath())
+	if err != nil {
+		if os.IsNotExist(err.Original()) {
+			return Config{}, nil
+		}
+		return Config{}, err
+	}
+	return NewConfigFromJson(configFile)
+}
+
 func (ctx *context) ManipulateBookmarks(manipulate func(BookmarksCollection) Error) Error {
 	bc, bErr := ctx.ReadBookmarks()
 	if bErr != nil {
@@ -252,6 +381,10 @@ func (ctx *context) bookmarkDatabasePath() File {
 	return NewFileOrPanic(ctx.KlogFolder() + "bookmarks.json")
 }
 
+func (ctx *context) configPath() File {
+	return NewFileOrPanic(ctx.KlogFolder() + "config.json")
+}
+
 func (ctx *context) OpenInFileBrowser(target File) Error {
 	cmd := exec.Command("open", target.Location())
 	err := cmd.Run()
@@ -293,18 +426,16 @@ func (ctx *context) OpenInEditor(fileArg FileOrBookmarkName, printHint func(stri
 	)
 }
 
-func (ctx *context) InstantiateTemplate(templateName string) ([]parsing.Text, Error) {
-	location := NewFileOrPanic(ctx.KlogFolder() + templateName + ".template.klg")
-	template, err := ReadFile(location)
+func (ctx *context) InstantiateTemplate(templateName string, variables map[string]string) ([]parsing.Text, error) {
+	template, err := ctx.ReadTemplate(templateName)
 	if err != nil {
-		return nil, NewError(
-			"No such template",
-			"There is no template at location "+location.Path(),
-			err,
-		)
+		return nil, err
 	}
-	instance, tErr := parser.RenderTemplate(template, ctx.Now())
+	instance, tErr := parser.RenderTemplate(template, variables)
 	if tErr != nil {
+		if parserErrors, isParserErrors := tErr.(parsing.Errors); isParserErrors {
+			return nil, NewFileErrors(ctx.templateFile(templateName), parserErrors)
+		}
 		return nil, NewError(
 			"Invalid template",
 			tErr.Error(),
@@ -314,6 +445,64 @@ func (ctx *context) InstantiateTemplate(templateName string) ([]parsing.Text, Er
 	return instance, nil
 }
 
+// TemplateNames returns the names of all templates in the klog folder.
+func (ctx *context) TemplateNames() []string {
+	paths, _ := filepath.Glob(ctx.KlogFolder() + "*" + TEMPLATE_FILE_SUFFIX)
+	var names []string
+	for _, p := range paths {
+		names = append(names, strings.TrimSuffix(filepath.Base(p), TEMPLATE_FILE_SUFFIX))
+	}
+	return names
+}
+
+func (ctx *context) ReadTemplate(templateName string) (string, Error) {
+	location := ctx.templateFile(templateName)
+	template, err := ReadFile(location)
+	if err != nil {
+		return "", NewErrorWithCode(
+			NO_SUCH_FILE,
+			"No such template",
+			"There is no template at location "+location.Path(),
+			err,
+		)
+	}
+	return template, nil
+}
+
+func (ctx *context) WriteTemplate(templateName string, contents string) Error {
+	iErr := ctx.initialiseKlogFolder()
+	if iErr != nil {
+		return iErr
+	}
+	return WriteToFile(ctx.templateFile(templateName), contents)
+}
+
+func (ctx *context) DeleteTemplate(templateName string) Error {
+	location := ctx.templateFile(templateName)
+	err := os.Remove(location.Path())
+	if os.IsNotExist(err) {
+		return NewErrorWithCode(
+			NO_SUCH_FILE,
+			"No such template",
+			"There is no template at location "+location.Path(),
+			err,
+		)
+	}
+	if err != nil {
+		return NewErrorWithCode(
+			IO_ERROR,
+			"Cannot delete template",
+			"Location: "+location.Path(),
+			err,
+		)
+	}
+	return nil
+}
+
+func (ctx *context) templateFile(templateName string) File {
+	return NewFileOrPanic(ctx.KlogFolder() + templateName + TEMPLATE_FILE_SUFFIX)
+}
+
 func (ctx *context) Serialiser() *parser.Serialiser {
 	return ctx.serialiser
 }
@@ -324,3 +513,9 @@ func (ctx *context) SetSerialiser(serialiser *parser.Serialiser) {
 	}
 	ctx.serialiser = serialiser
 }
+
+// SetCommandName sets the name of the invoked (sub)command, e.g. `track`,
+// which is used in git commit messages.
+func (ctx *context) SetCommandName(name string) {
+	ctx.commandName = name
+}
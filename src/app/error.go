@@ -1,5 +1,7 @@
 package app
 
+import "github.com/jotaen/klog/src/parser/parsing"
+
 type Code int
 
 const (
@@ -53,3 +55,14 @@ func (e AppError) Original() error {
 func (e AppError) Code() Code {
 	return e.code
 }
+
+// FileErrors are the parser errors that occurred when reading a file.
+// File is nil if the input was read from stdin.
+type FileErrors struct {
+	parsing.Errors
+	File File
+}
+
+func NewFileErrors(file File, errs parsing.Errors) FileErrors {
+	return FileErrors{errs, file}
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/parser"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// runGit executes a git command in the given directory, and returns its output.
+var runGit = func(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// gitSettingsFor returns the git settings that apply to the file.
+func (c Config) gitSettingsFor(bc BookmarksCollection, file File) GitSettings {
+	for _, b := range bc.All() {
+		settings, ok := c.BookmarkGit[b.Name()]
+		if ok && b.Target().Path() == file.Path() {
+			return settings
+		}
+	}
+	return c.Git
+}
+
+// commitToGit commits the file, if it is located in a git repository. The
+// commit is skipped if other changes are staged, since these would otherwise
+// be committed as well.
+func (ctx *context) commitToGit(target File, message string, push bool) Error {
+	dir := filepath.Dir(target.Path())
+	root, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil // Not a git repository
+	}
+	path := target.Path()
+	if evaluated, eErr := filepath.EvalSymlinks(path); eErr == nil {
+		path = evaluated
+	}
+	if evaluated, eErr := filepath.EvalSymlinks(root); eErr == nil {
+		root = evaluated
+	}
+	relativePath, _ := filepath.Rel(root, path)
+	staged, err := runGit(dir, "diff", "--cached", "--name-only")
+	if err != nil {
+		return newGitError("Cannot commit file to git", staged, err)
+	}
+	for _, s := range strings.Split(staged, "\n") {
+		if s != "" && s != filepath.ToSlash(relativePath) {
+			ctx.Print("Note: The file was not committed to git, because there are other staged changes.\n")
+			return nil
+		}
+	}
+	if out, err := runGit(dir, "add", "--", path); err != nil {
+		return newGitError("Cannot commit file to git", out, err)
+	}
+	if out, err := runGit(dir, "commit", "--quiet", "-m", message, "--", path); err != nil {
+		return newGitError("Cannot commit file to git", out, err)
+	}
+	if push {
+		if out, err := runGit(dir, "push", "--quiet"); err != nil {
+			return newGitError("Cannot push to git remote", out, err)
+		}
+	}
+	return nil
+}
+
+func newGitError(message string, output string, err error) Error {
+	return NewErrorWithCode(
+		IO_ERROR,
+		message,
+		"The file itself was saved successfully. Git reported:\n"+output,
+		err,
+	)
+}
+
+// ReadFileRevision reads a file (or bookmark) in the version of the given
+// git revision, e.g. `HEAD~1`.
+func (ctx *context) ReadFileRevision(revision string, fileArg FileOrBookmarkName) (*parser.ParseResult, File, error) {
+	bc, err := ctx.ReadBookmarks()
+	if err != nil {
+		return nil, nil, err
+	}
+	readRevision := func(f File) (string, Error) {
+		out, gErr := runGit(f.Location(), "show", revision+":./"+f.Name())
+		if gErr != nil {
+			return "", NewErrorWithCode(
+				NO_SUCH_FILE,
+				"Cannot read git revision "+revision,
+				out,
+				gErr,
+			)
+		}
+		return out, nil
+	}
+	inputs, err := (&fileRetriever{readRevision, bc}).Retrieve(fileArg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(inputs) == 0 {
+		return nil, nil, NewErrorWithCode(
+			NO_TARGET_FILE,
+			"No file specified",
+			"Either specify a file name or bookmark name, or set a default bookmark",
+			nil,
+		)
+	}
+	pr, parserErrors := parser.Parse(inputs[0].content)
+	if parserErrors != nil {
+		return nil, nil, NewFileErrors(inputs[0].File, parserErrors)
+	}
+	return pr, inputs[0].File, nil
+}
+
+// commitMessage describes the change of a file, e.g.:
+// `klog track 2020-01-01: 1h #work`
+func commitMessage(command string, originalText string, newText string) string {
+	originalLines := strings.Split(originalText, "\n")
+	newLines := strings.Split(newText, "\n")
+	start := 0
+	for start < len(originalLines) && start < len(newLines) && originalLines[start] == newLines[start] {
+		start++
+	}
+	originalEnd, newEnd := len(originalLines), len(newLines)
+	for originalEnd > start && newEnd > start && originalLines[originalEnd-1] == newLines[newEnd-1] {
+		originalEnd--
+		newEnd--
+	}
+
+	var date Date
+	var changes []string
+	for _, l := range newLines[start:newEnd] {
+		if d := headlineDate(l); d != nil {
+			date = d
+			continue
+		}
+		if strings.TrimSpace(l) != "" {
+			changes = append(changes, strings.TrimSpace(l))
+		}
+	}
+	if date == nil {
+		// The change is within a record, so the date is in the closest
+		// headline above (in either version of the text).
+		lines := newLines[:start]
+		if newEnd == start {
+			lines = originalLines[:originalEnd]
+		}
+		for i := len(lines) - 1; i >= 0 && date == nil; i-- {
+			date = headlineDate(lines[i])
+		}
+	}
+
+	message := "klog"
+	if command != "" {
+		message += " " + command
+	}
+	if date != nil {
+		message += " " + date.ToString()
+	}
+	if len(changes) > 0 {
+		message += ": " + strings.Join(changes, "; ")
+	}
+	return message
+}
+
+// headlineDate returns the date of a record headline, or nil if the line
+// is not a headline.
+func headlineDate(line string) Date {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || line[0] == ' ' || line[0] == '\t' {
+		return nil
+	}
+	d, err := NewDateFromString(fields[0])
+	if err != nil {
+		return nil
+	}
+	return d
+}
@@ -0,0 +1,184 @@
+package app
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommitMessageForNewEntry(t *testing.T) {
+	message := commitMessage("track",
+		"2020-01-01\n    2h\n\n2020-01-02\n    1h\n",
+		"2020-01-01\n    2h\n\n2020-01-02\n    1h\n    30m #clientA\n")
+	assert.Equal(t, "klog track 2020-01-02: 30m #clientA", message)
+}
+
+func TestCommitMessageForChangedEntry(t *testing.T) {
+	message := commitMessage("stop",
+		"2020-01-01\n    8:00 - ?\n    1h\n",
+		"2020-01-01\n    8:00 - 9:30 Meeting\n    1h\n")
+	assert.Equal(t, "klog stop 2020-01-01: 8:00 - 9:30 Meeting", message)
+}
+
+func TestCommitMessageForNewRecord(t *testing.T) {
+	message := commitMessage("create",
+		"2020-01-01\n    2h\n",
+		"2020-01-01\n    2h\n\n2020-01-02 (8h!)\n")
+	assert.Equal(t, "klog create 2020-01-02", message)
+}
+
+func TestCommitMessageForRemovedRecord(t *testing.T) {
+	message := commitMessage("delete",
+		"2020-01-01\n    2h\n\n2020-01-02\n    1h\n\n2020-01-03\n    3h\n",
+		"2020-01-01\n    2h\n\n2020-01-03\n    3h\n")
+	assert.Equal(t, "klog delete 2020-01-02", message)
+}
+
+func TestCommitMessageWithoutCommand(t *testing.T) {
+	assert.Equal(t, "klog 2020-01-01: 1h", commitMessage("", "", "2020-01-01\n    1h\n"))
+}
+
+func TestParsesGitSettings(t *testing.T) {
+	config, err := NewConfigFromJson(`{
+  "git": {"auto_commit": true},
+  "bookmarks": {"@work": {"git": {"auto_commit": true, "auto_push": true}}, "side": {"git": {}}}
+}`)
+	require.Nil(t, err)
+	assert.Equal(t, GitSettings{AutoCommit: true}, config.Git)
+	assert.Equal(t, map[Name]GitSettings{
+		"work": {AutoCommit: true, AutoPush: true},
+		"side": {},
+	}, config.BookmarkGit)
+
+	bc := NewEmptyBookmarksCollection()
+	bc.Set(NewBookmark("work", NewFileOrPanic("/work.klg")))
+	bc.Set(NewBookmark("side", NewFileOrPanic("/side.klg")))
+	assert.Equal(t, GitSettings{AutoCommit: true, AutoPush: true}, config.gitSettingsFor(bc, NewFileOrPanic("/work.klg")))
+	assert.Equal(t, GitSettings{}, config.gitSettingsFor(bc, NewFileOrPanic("/side.klg")))
+	assert.Equal(t, GitSettings{AutoCommit: true}, config.gitSettingsFor(bc, NewFileOrPanic("/other.klg")))
+}
+
+// fakeGit substitutes the git binary, and records all invocations.
+func fakeGit(t *testing.T, responses map[string]string) *[]string {
+	var calls []string
+	original := runGit
+	t.Cleanup(func() { runGit = original })
+	runGit = func(dir string, args ...string) (string, error) {
+		call := strings.Join(args, " ")
+		calls = append(calls, call)
+		for prefix, response := range responses {
+			if strings.HasPrefix(call, prefix) {
+				if response == "ERROR" {
+					return "fatal", errors.New("exit status 1")
+				}
+				return response, nil
+			}
+		}
+		return "", nil
+	}
+	return &calls
+}
+
+func TestCommitsFileToGit(t *testing.T) {
+	calls := fakeGit(t, map[string]string{"rev-parse": "/repo", "diff": "times.klg"})
+	err := (&context{}).commitToGit(NewFileOrPanic("/repo/times.klg"), "klog track", true)
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		"rev-parse --show-toplevel",
+		"diff --cached --name-only",
+		"add -- /repo/times.klg",
+		"commit --quiet -m klog track -- /repo/times.klg",
+		"push --quiet",
+	}, *calls)
+}
+
+func TestSkipsCommitOutsideOfRepository(t *testing.T) {
+	calls := fakeGit(t, map[string]string{"rev-parse": "ERROR"})
+	err := (&context{}).commitToGit(NewFileOrPanic("/repo/times.klg"), "klog track", false)
+	require.Nil(t, err)
+	assert.Len(t, *calls, 1)
+}
+
+func TestSkipsCommitIfOtherChangesAreStaged(t *testing.T) {
+	calls := fakeGit(t, map[string]string{"rev-parse": "/repo", "diff": "times.klg\nother.txt"})
+	err := (&context{}).commitToGit(NewFileOrPanic("/repo/times.klg"), "klog track", false)
+	require.Nil(t, err)
+	assert.Len(t, *calls, 2)
+}
+
+func TestReportsFailingCommit(t *testing.T) {
+	fakeGit(t, map[string]string{"rev-parse": "/repo", "commit": "ERROR"})
+	err := (&context{}).commitToGit(NewFileOrPanic("/repo/times.klg"), "klog track", false)
+	require.Error(t, err)
+	assert.Equal(t, "Cannot commit file to git", err.Error())
+}
+
+func TestWriteFileOnlyWarnsIfCommitFails(t *testing.T) {
+	home := t.TempDir()
+	require.Nil(t, os.MkdirAll(filepath.Join(home, ".klog"), 0700))
+	require.Nil(t, os.WriteFile(filepath.Join(home, ".klog", "config.json"), []byte(`{"git": {"auto_commit": true}}`), 0600))
+	repo := t.TempDir()
+	calls := fakeGit(t, map[string]string{"rev-parse": repo, "commit": "ERROR"})
+	ctx, _ := NewContext(home, nil)
+	target := NewFileOrPanic(filepath.Join(repo, "times.klg"))
+
+	err := ctx.WriteFile(target, "2020-01-01\n")
+	require.Nil(t, err)
+	require.Len(t, *calls, 4)
+	assert.True(t, strings.HasPrefix((*calls)[3], "commit"))
+	contents, _ := ReadFile(target)
+	assert.Equal(t, "2020-01-01\n", contents)
+}
+
+func TestWriteFileUsesCommandNameInCommitMessage(t *testing.T) {
+	home := t.TempDir()
+	require.Nil(t, os.MkdirAll(filepath.Join(home, ".klog"), 0700))
+	require.Nil(t, os.WriteFile(filepath.Join(home, ".klog", "config.json"), []byte(`{"git": {"auto_commit": true}}`), 0600))
+	repo := t.TempDir()
+	calls := fakeGit(t, map[string]string{"rev-parse": repo})
+	ctx, _ := NewContext(home, nil)
+	ctx.SetCommandName("bookmarks set")
+	target := NewFileOrPanic(filepath.Join(repo, "times.klg"))
+
+	err := ctx.WriteFile(target, "2020-01-01\n")
+	require.Nil(t, err)
+	require.Len(t, *calls, 4)
+	assert.Equal(t, "commit --quiet -m klog bookmarks set 2020-01-01 -- "+target.Path(), (*calls)[3])
+}
+
+func TestWriteFileWarnsIfConfigIsBroken(t *testing.T) {
+	home := t.TempDir()
+	require.Nil(t, os.MkdirAll(filepath.Join(home, ".klog"), 0700))
+	require.Nil(t, os.WriteFile(filepath.Join(home, ".klog", "config.json"), []byte(`{"git": `), 0600))
+	calls := fakeGit(t, nil)
+	ctx, _ := NewContext(home, nil)
+	target := NewFileOrPanic(filepath.Join(t.TempDir(), "times.klg"))
+
+	output := captureStdout(t, func() {
+		err := ctx.WriteFile(target, "2020-01-01\n")
+		require.Nil(t, err)
+	})
+	assert.True(t, strings.HasPrefix(output, "Warning: Cannot determine whether to commit the file to git."))
+	assert.Empty(t, *calls)
+	contents, _ := ReadFile(target)
+	assert.Equal(t, "2020-01-01\n", contents)
+}
+
+// captureStdout returns everything that is printed while running fn.
+func captureStdout(t *testing.T, fn func()) string {
+	original := os.Stdout
+	r, w, err := os.Pipe()
+	require.Nil(t, err)
+	os.Stdout = w
+	defer func() { os.Stdout = original }()
+	fn()
+	require.Nil(t, w.Close())
+	output, err := io.ReadAll(r)
+	require.Nil(t, err)
+	return string(output)
+}
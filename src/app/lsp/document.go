@@ -0,0 +1,250 @@
+package lsp
+
+import (
+	"fmt"
+	"github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/parser"
+	"github.com/jotaen/klog/src/parser/parsing"
+	"github.com/jotaen/klog/src/service"
+	"sort"
+	"strings"
+	gotime "time"
+)
+
+// document is the parsed state of a file that is opened in the editor.
+// Note that the character positions are counted in runes, which only
+// deviates from the protocol’s UTF-16 code units for very unusual characters.
+type document struct {
+	lines []string
+	pr    *parser.ParseResult
+	errs  parsing.Errors
+
+	// tags are the known tags of the file. They are retained from the
+	// previous state if the file currently has errors.
+	tags []klog.Tag
+}
+
+func newDocument(text string, previous *document) *document {
+	pr, errs := parser.Parse(text)
+	doc := &document{
+		lines: strings.Split(text, "\n"),
+		pr:    pr,
+		errs:  errs,
+	}
+	if errs != nil {
+		if previous != nil {
+			doc.tags = previous.tags
+		}
+		return doc
+	}
+	entriesByTag, _ := service.EntryTagLookup(pr.Records...)
+	for t := range entriesByTag {
+		doc.tags = append(doc.tags, t)
+	}
+	sort.Slice(doc.tags, func(i, j int) bool { return doc.tags[i] < doc.tags[j] })
+	return doc
+}
+
+func (d *document) diagnostics(now gotime.Time) []Diagnostic {
+	result := []Diagnostic{}
+	if d.errs != nil {
+		for _, e := range d.errs.Get() {
+			start := Position{e.Context().LineNumber - 1, e.Column() - 1}
+			result = append(result, Diagnostic{
+				Range:    Range{start, Position{start.Line, start.Character + e.Length()}},
+				Severity: SeverityError,
+				Source:   "klog",
+				Message:  e.Title() + ": " + e.Details(),
+			})
+		}
+		return result
+	}
+	for _, w := range service.SanityCheck(now, d.pr.Records) {
+		for i, r := range d.pr.Records {
+			if !r.Date().IsEqualTo(w.Date) {
+				continue
+			}
+			headline, _ := d.pr.LinesOfRecord(i)
+			result = append(result, Diagnostic{
+				Range:    d.lineRange(headline - 1),
+				Severity: SeverityWarning,
+				Source:   "klog",
+				Message:  w.Message,
+			})
+		}
+	}
+	return result
+}
+
+// recordAt returns the index of the record that the line belongs to, or -1.
+func (d *document) recordAt(line int) int {
+	if d.pr == nil {
+		return -1
+	}
+	for i := range d.pr.Records {
+		first, last := d.pr.LinesOfRecord(i)
+		if line+1 >= first && line+1 <= last {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *document) hover(pos Position, now gotime.Time) *Hover {
+	i := d.recordAt(pos.Line)
+	if i == -1 {
+		return nil
+	}
+	r := d.pr.Records[i]
+	text := "**Total:** " + service.Total(r).ToString()
+	if r.OpenRange() != nil {
+		hypothetical, _ := service.HypotheticalTotal(now, r)
+		text += " (" + hypothetical.ToString() + " including the open range until now)"
+	}
+	if r.HasShouldTotal() {
+		text += "  \n**Should:** " + r.ShouldTotal().ToString()
+		text += "  \n**Diff:** " + service.Diff(r.ShouldTotal(), service.Total(r)).ToStringWithSign()
+	}
+	first, last := d.pr.LinesOfRecord(i)
+	return &Hover{
+		Contents: MarkupContent{"markdown", text},
+		Range:    Range{Position{first - 1, 0}, d.lineRange(last - 1).End},
+	}
+}
+
+// inlayHints shows the total time (and diff, if applicable) behind the headline
+// of all records in the given range.
+func (d *document) inlayHints(rng Range, now gotime.Time) []InlayHint {
+	result := []InlayHint{}
+	if d.pr == nil {
+		return result
+	}
+	for i, r := range d.pr.Records {
+		headline, _ := d.pr.LinesOfRecord(i)
+		if headline-1 < rng.Start.Line || headline-1 > rng.End.Line {
+			continue
+		}
+		total, _ := service.HypotheticalTotal(now, r)
+		label := "= " + total.ToString()
+		if r.HasShouldTotal() {
+			label += fmt.Sprintf(" (%s)", service.Diff(r.ShouldTotal(), total).ToStringWithSign())
+		}
+		result = append(result, InlayHint{
+			Position:    d.lineRange(headline - 1).End,
+			Label:       label,
+			PaddingLeft: true,
+		})
+	}
+	return result
+}
+
+// completion suggests the known tags, if the cursor is behind a `#`.
+func (d *document) completion(pos Position) []CompletionItem {
+	result := []CompletionItem{}
+	if pos.Line >= len(d.lines) {
+		return result
+	}
+	line := []rune(d.lines[pos.Line])
+	if pos.Character > len(line) {
+		return result
+	}
+	start := pos.Character
+	for start > 0 && line[start-1] != '#' && klog.HashTagPattern.MatchString("#"+string(line[start-1])) {
+		start--
+	}
+	if start == 0 || line[start-1] != '#' {
+		return result
+	}
+	rng := Range{Position{pos.Line, start - 1}, pos}
+	for _, t := range d.tags {
+		result = append(result, CompletionItem{
+			Label:    t.ToString(),
+			Kind:     14, // Keyword
+			TextEdit: TextEdit{rng, t.ToString()},
+		})
+	}
+	return result
+}
+
+func (d *document) formatting() []TextEdit {
+	if d.pr == nil {
+		return []TextEdit{}
+	}
+	result, err := parser.NewFormattingReconciler(d.pr).Format()
+	if err != nil || result.NewText == d.pr.Text() {
+		return []TextEdit{}
+	}
+	return []TextEdit{d.replaceAll(result.NewText)}
+}
+
+func (d *document) codeActions(uri string, rng Range, now gotime.Time) []CodeAction {
+	result := []CodeAction{}
+	if d.pr == nil {
+		return result
+	}
+	seen := make(map[int]bool)
+	for line := rng.Start.Line; line <= rng.End.Line; line++ {
+		i := d.recordAt(line)
+		if i == -1 || seen[i] || d.pr.Records[i].OpenRange() == nil {
+			continue
+		}
+		seen[i] = true
+		if action := d.closeOpenRangeAction(uri, i, now); action != nil {
+			result = append(result, *action)
+		}
+	}
+	if edits := d.formatting(); len(edits) > 0 {
+		result = append(result, CodeAction{
+			Title: "Format file",
+			Kind:  "source",
+			Edit:  WorkspaceEdit{map[string][]TextEdit{uri: edits}},
+		})
+	}
+	return result
+}
+
+// closeOpenRangeAction closes the open range of the record with the current
+// time, if the record is dated today or yesterday.
+func (d *document) closeOpenRangeAction(uri string, recordIndex int, now gotime.Time) *CodeAction {
+	record := d.pr.Records[recordIndex]
+	today := klog.NewDateFromTime(now)
+	end := klog.NewTimeFromTime(now)
+	if today.PlusDays(-1).IsEqualTo(record.Date()) {
+		end, _ = end.Add(klog.NewDuration(24, 0))
+	} else if !today.IsEqualTo(record.Date()) {
+		return nil
+	}
+	// The reconciler modifies the lines of the parse result, so it needs a fresh one.
+	pr, errs := parser.Parse(d.pr.Text())
+	if errs != nil {
+		return nil
+	}
+	reconciler := parser.NewRecordReconciler(pr, func(r klog.Record) bool {
+		return r == pr.Records[recordIndex]
+	})
+	result, err := reconciler.CloseOpenRange(func(klog.Record) (klog.Time, klog.Summary) { return end, "" })
+	if err != nil {
+		return nil
+	}
+	return &CodeAction{
+		Title: "Close open range now (" + end.ToString() + ")",
+		Kind:  "quickfix",
+		Edit:  WorkspaceEdit{map[string][]TextEdit{uri: {d.replaceAll(result.NewText)}}},
+	}
+}
+
+func (d *document) lineRange(line int) Range {
+	length := 0
+	if line < len(d.lines) {
+		length = len([]rune(strings.TrimRight(d.lines[line], "\r")))
+	}
+	return Range{Position{line, 0}, Position{line, length}}
+}
+
+func (d *document) replaceAll(text string) TextEdit {
+	last := len(d.lines) - 1
+	return TextEdit{
+		Range:   Range{Position{0, 0}, d.lineRange(last).End},
+		NewText: text,
+	}
+}
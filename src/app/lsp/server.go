@@ -0,0 +1,168 @@
+/*
+Package lsp implements a language server for klog files, which editors can
+communicate with via the Language Server Protocol (over stdio).
+*/
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	gotime "time"
+)
+
+type Server struct {
+	now       func() gotime.Time
+	out       io.Writer
+	documents map[string]*document
+}
+
+func NewServer(now func() gotime.Time) *Server {
+	return &Server{
+		now:       now,
+		documents: make(map[string]*document),
+	}
+}
+
+// Run processes the incoming messages, until the client either sends
+// the `exit` notification or closes the input stream.
+func (s *Server) Run(in io.Reader, out io.Writer) error {
+	s.out = out
+	reader := bufio.NewReader(in)
+	for {
+		m, err := readMessage(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if m.Method == "exit" {
+			return nil
+		}
+		if m.Id == nil {
+			s.handleNotification(m)
+			continue
+		}
+		result, rErr := s.handleRequest(m)
+		if rErr != nil {
+			err = writeMessage(s.out, errorResponse{"2.0", m.Id, *rErr})
+		} else {
+			err = writeMessage(s.out, response{"2.0", m.Id, result})
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handleNotification(m message) {
+	switch m.Method {
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if json.Unmarshal(m.Params, &params) == nil {
+			s.update(params.TextDocument.Uri, params.TextDocument.Text)
+		}
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if json.Unmarshal(m.Params, &params) == nil && len(params.ContentChanges) > 0 {
+			// The server only supports full synchronisation, so the last
+			// change always contains the entire text.
+			s.update(params.TextDocument.Uri, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if json.Unmarshal(m.Params, &params) == nil {
+			delete(s.documents, params.TextDocument.Uri)
+			s.publishDiagnostics(params.TextDocument.Uri, []Diagnostic{})
+		}
+	}
+}
+
+func (s *Server) handleRequest(m message) (interface{}, *responseError) {
+	switch m.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":           1, // Full
+				"hoverProvider":              true,
+				"inlayHintProvider":          true,
+				"documentFormattingProvider": true,
+				"codeActionProvider":         true,
+				"completionProvider": map[string]interface{}{
+					"triggerCharacters": []string{"#"},
+				},
+			},
+			"serverInfo": map[string]string{"name": "klog"},
+		}, nil
+	case "shutdown":
+		return nil, nil
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		doc, err := s.params(m, &params, &params.TextDocument)
+		if err != nil {
+			return nil, err
+		}
+		if hover := doc.hover(params.Position, s.now()); hover != nil {
+			return hover, nil
+		}
+		return nil, nil
+	case "textDocument/inlayHint":
+		var params TextDocumentRangeParams
+		doc, err := s.params(m, &params, &params.TextDocument)
+		if err != nil {
+			return nil, err
+		}
+		return doc.inlayHints(params.Range, s.now()), nil
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		doc, err := s.params(m, &params, &params.TextDocument)
+		if err != nil {
+			return nil, err
+		}
+		return doc.completion(params.Position), nil
+	case "textDocument/formatting":
+		var params TextDocumentRangeParams
+		doc, err := s.params(m, &params, &params.TextDocument)
+		if err != nil {
+			return nil, err
+		}
+		return doc.formatting(), nil
+	case "textDocument/codeAction":
+		var params TextDocumentRangeParams
+		doc, err := s.params(m, &params, &params.TextDocument)
+		if err != nil {
+			return nil, err
+		}
+		return doc.codeActions(params.TextDocument.Uri, params.Range, s.now()), nil
+	}
+	return nil, &responseError{errorMethodNotFound, "Method not supported: " + m.Method}
+}
+
+// params decodes the parameters of a request, and returns the document that
+// the request refers to.
+func (s *Server) params(m message, params interface{}, id *TextDocumentIdentifier) (*document, *responseError) {
+	if err := json.Unmarshal(m.Params, params); err != nil {
+		return nil, &responseError{errorInvalidParams, err.Error()}
+	}
+	doc, ok := s.documents[id.Uri]
+	if !ok {
+		return nil, &responseError{errorInvalidParams, "Unknown document: " + id.Uri}
+	}
+	return doc, nil
+}
+
+func (s *Server) update(uri string, text string) {
+	previous := s.documents[uri]
+	doc := newDocument(text, previous)
+	s.documents[uri] = doc
+	s.publishDiagnostics(uri, doc.diagnostics(s.now()))
+}
+
+func (s *Server) publishDiagnostics(uri string, diagnostics []Diagnostic) {
+	_ = writeMessage(s.out, notification{
+		JsonRpc: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  PublishDiagnosticsParams{uri, diagnostics},
+	})
+}
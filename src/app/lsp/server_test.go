@@ -0,0 +1,138 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+	gotime "time"
+)
+
+const uri = "file:///times.klg"
+
+// runSession sends the messages to a server and returns the bodies of all
+// messages that the server sent back.
+func runSession(t *testing.T, messages ...string) []string {
+	in := ""
+	for _, m := range messages {
+		in += fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(m), m)
+	}
+	out := new(bytes.Buffer)
+	now := gotime.Date(2020, 1, 2, 18, 30, 0, 0, gotime.UTC)
+	err := NewServer(func() gotime.Time { return now }).Run(strings.NewReader(in), out)
+	require.Nil(t, err)
+	var bodies []string
+	reader := bufio.NewReader(out)
+	for {
+		var length int
+		if _, err := fmt.Fscanf(reader, "Content-Length: %d\r\n\r\n", &length); err != nil {
+			break
+		}
+		body := make([]byte, length)
+		_, _ = io.ReadFull(reader, body)
+		bodies = append(bodies, string(body))
+	}
+	return bodies
+}
+
+func didOpen(text string) string {
+	params, _ := json.Marshal(DidOpenTextDocumentParams{TextDocumentItem{uri, text}})
+	return `{"jsonrpc":"2.0","method":"textDocument/didOpen","params":` + string(params) + `}`
+}
+
+func request(id int, method string, params string) string {
+	return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"%s","params":%s}`, id, method, params)
+}
+
+// sendAndReceive opens a document, sends a request, and returns the response.
+func sendAndReceive(t *testing.T, text string, method string, params string) string {
+	out := runSession(t, didOpen(text), request(1, method, params))
+	require.Len(t, out, 2)
+	return out[1]
+}
+
+func TestInitialize(t *testing.T) {
+	out := runSession(t,
+		request(1, "initialize", `{}`),
+		request(2, "shutdown", `null`),
+		`{"jsonrpc":"2.0","method":"exit"}`,
+		request(3, "shutdown", `null`),
+	)
+	require.Len(t, out, 2)
+	assert.Contains(t, out[0], `"id":1,"result":{"capabilities":{`)
+	assert.Contains(t, out[0], `"hoverProvider":true`)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":2,"result":null}`, out[1])
+}
+
+func TestRejectsUnknownMethod(t *testing.T) {
+	out := sendAndReceive(t, "", "foo/bar", `{}`)
+	assert.Contains(t, out, `"error":{"code":-32601`)
+}
+
+func TestPublishesParserErrors(t *testing.T) {
+	out := runSession(t, didOpen("2020-01-01\n    1h\n    asdf\n"))
+	require.Len(t, out, 1)
+	assert.Contains(t, out[0], `"method":"textDocument/publishDiagnostics","params":{"uri":"`+uri+`","diagnostics":[{`+
+		`"range":{"start":{"line":2,"character":4},"end":{"line":2,"character":8}},"severity":1,"source":"klog","message":"Malformed entry: `)
+}
+
+func TestPublishesWarnings(t *testing.T) {
+	out := runSession(t, didOpen("2019-12-01\n    8:00 - ?\n"))
+	require.Len(t, out, 1)
+	assert.Contains(t, out[0], `"diagnostics":[{`+
+		`"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":10}},"severity":2,"source":"klog","message":"Unclosed open range"}]`)
+}
+
+func TestHoverShowsTotals(t *testing.T) {
+	out := sendAndReceive(t, "2020-01-01 (8h!)\n    8:00 - 15:30\n", "textDocument/hover",
+		`{"textDocument":{"uri":"`+uri+`"},"position":{"line":1,"character":3}}`)
+	assert.Contains(t, out, `"value":"**Total:** 7h30m  \n**Should:** 8h!  \n**Diff:** -30m"`)
+
+	out = sendAndReceive(t, "2020-01-01\n\n2020-01-02\n", "textDocument/hover",
+		`{"textDocument":{"uri":"`+uri+`"},"position":{"line":1,"character":0}}`)
+	assert.Contains(t, out, `"result":null`)
+}
+
+func TestInlayHints(t *testing.T) {
+	out := sendAndReceive(t, "2020-01-01 (8h!)\n    8:00 - 15:30\n\n2020-01-02\n    17:00 - ?\n", "textDocument/inlayHint",
+		`{"textDocument":{"uri":"`+uri+`"},"range":{"start":{"line":0,"character":0},"end":{"line":10,"character":0}}}`)
+	assert.Contains(t, out, `"result":[`+
+		`{"position":{"line":0,"character":16},"label":"= 7h30m (-30m)","paddingLeft":true},`+
+		`{"position":{"line":3,"character":10},"label":"= 1h30m","paddingLeft":true}]`)
+}
+
+func TestCompletesTags(t *testing.T) {
+	text := "2020-01-01\nWork #project\n    1h #meeting\n    2h #me"
+	out := sendAndReceive(t, text, "textDocument/completion",
+		`{"textDocument":{"uri":"`+uri+`"},"position":{"line":3,"character":10}}`)
+	assert.Contains(t, out, `"result":[`+
+		`{"label":"#me","kind":14,"textEdit":{"range":{"start":{"line":3,"character":7},"end":{"line":3,"character":10}},"newText":"#me"}},`+
+		`{"label":"#meeting","kind":14,"textEdit":{"range":{"start":{"line":3,"character":7},"end":{"line":3,"character":10}},"newText":"#meeting"}},`+
+		`{"label":"#project",`)
+
+	out = sendAndReceive(t, text, "textDocument/completion",
+		`{"textDocument":{"uri":"`+uri+`"},"position":{"line":2,"character":4}}`)
+	assert.Contains(t, out, `"result":[]`)
+}
+
+func TestFormatting(t *testing.T) {
+	out := sendAndReceive(t, "2020-01-01  \n  1h\n\n\n2020-01-02\n  2h\n", "textDocument/formatting",
+		`{"textDocument":{"uri":"`+uri+`"}}`)
+	assert.Contains(t, out, `"result":[{"range":{"start":{"line":0,"character":0},"end":{"line":6,"character":0}},"newText":"2020-01-01\n  1h\n\n2020-01-02\n  2h\n"}]`)
+}
+
+func TestCodeActionClosesOpenRange(t *testing.T) {
+	out := sendAndReceive(t, "2020-01-01\n    1h\n\n2020-01-02\n    17:00 - ?\n", "textDocument/codeAction",
+		`{"textDocument":{"uri":"`+uri+`"},"range":{"start":{"line":4,"character":0},"end":{"line":4,"character":0}}}`)
+	assert.Contains(t, out, `"result":[{"title":"Close open range now (18:30)","kind":"quickfix","edit":{"changes":{"`+uri+`":[`+
+		`{"range":{"start":{"line":0,"character":0},"end":{"line":5,"character":0}},"newText":"2020-01-01\n    1h\n\n2020-01-02\n    17:00 - 18:30\n"}]}}}]`)
+
+	out = sendAndReceive(t, "2020-01-01\n    1h\n\n2020-01-02\n    17:00 - ?\n", "textDocument/codeAction",
+		`{"textDocument":{"uri":"`+uri+`"},"range":{"start":{"line":1,"character":0},"end":{"line":1,"character":0}}}`)
+	assert.Contains(t, out, `"result":[]`)
+}
@@ -82,7 +82,8 @@ func renderRecords(ctx app.Context, records []klog.Record, file app.File) []menu
 		Text: file.Name(),
 		Children: func() []menuet2.MenuItem {
 			total := service.Total(records...)
-			should := service.ShouldTotalSum(records...)
+			config, _ := ctx.ReadConfig()
+			should := service.ShouldTotalSum(config.Schedule, records...)
 			diff := service.Diff(should, total)
 			plus := ""
 			if diff.InMinutes() > 0 {
@@ -40,6 +40,7 @@ func (retriever *fileRetriever) Retrieve(fileArgs ...FileOrBookmarkName) ([]*fil
 	}
 	var results []*fileWithContent
 	var errs []string
+	errCode := NO_SUCH_FILE // Only if all files are missing, otherwise `IO_ERROR`
 	for _, arg := range fileArgs {
 		argValue := string(arg)
 		path, pathErr := (func() (string, error) {
@@ -54,22 +55,27 @@ func (retriever *fileRetriever) Retrieve(fileArgs ...FileOrBookmarkName) ([]*fil
 		})()
 		if pathErr != nil {
 			errs = append(errs, pathErr.Error()+": "+argValue)
+			errCode = IO_ERROR
 			continue
 		}
 		file, fErr := NewFile(path)
 		if fErr != nil {
 			errs = append(errs, "Invalid file path: "+path)
+			errCode = IO_ERROR
 		}
 		content, readErr := retriever.readFile(file)
 		if readErr != nil {
 			errs = append(errs, readErr.Error()+": "+file.Path())
+			if readErr.Code() != NO_SUCH_FILE {
+				errCode = IO_ERROR
+			}
 			continue
 		}
 		results = append(results, &fileWithContent{file, content})
 	}
 	if len(errs) > 0 {
 		return nil, NewErrorWithCode(
-			IO_ERROR,
+			errCode,
 			"Cannot retrieve files",
 			strings.Join(errs, "\n"),
 			nil,
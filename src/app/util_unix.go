@@ -3,8 +3,33 @@
 
 package app
 
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
 var POTENTIAL_EDITORS = []string{"vim", "vi", "nano", "pico"}
 
 func flagAsHidden(path string) {
 	// Nothing to do on UNIX due to the dotfile convention
 }
+
+func terminalWidth() int {
+	var size struct {
+		rows    uint16
+		columns uint16
+		x       uint16
+		y       uint16
+	}
+	_, _, err := syscall.Syscall(
+		syscall.SYS_IOCTL,
+		os.Stdout.Fd(),
+		uintptr(syscall.TIOCGWINSZ),
+		uintptr(unsafe.Pointer(&size)),
+	)
+	if err != 0 {
+		return 0
+	}
+	return int(size.columns)
+}
@@ -16,3 +16,8 @@ func flagAsHidden(path string) {
 	}
 	_ = syscall.SetFileAttributes(winFileName, syscall.FILE_ATTRIBUTE_HIDDEN)
 }
+
+func terminalWidth() int {
+	// Not determined on Windows, so the `COLUMNS` variable or the default is used
+	return 0
+}
@@ -33,18 +33,28 @@ func ErrorMalformedShouldTotal(e Error) Error {
 func ErrorUnrecognisedProperty(e Error) Error {
 	return e.Set(
 		"ErrorUnrecognisedProperty",
-		"Unrecognised should-total value",
+		"Unrecognised property",
 		"The highlighted value is not recognised. "+
-			"The should-total must be a time duration suffixed with an "+
-			"exclamation mark, e.g. 5h15m! or 8h!",
+			"A should-total must be a time duration with an exclamation mark at the end, "+
+			"for example: (8h!) or (5h15m!). "+
+			"Other properties must be of the form key=value or @location, "+
+			"or a flag such as billable.",
+	)
+}
+
+func ErrorDuplicateProperty(e Error) Error {
+	return e.Set(
+		"ErrorDuplicateProperty",
+		"Duplicate property",
+		"A property can only be specified once per record.",
 	)
 }
 
 func ErrorMalformedPropertiesSyntax(e Error) Error {
 	return e.Set(
 		"ErrorMalformedPropertiesSyntax",
-		"Malformed should-total time",
-		"The should-total cannot be empty and it must be "+
+		"Malformed properties",
+		"The properties cannot be empty and they must be "+
 			"surrounded by parenthesis on both sides",
 	)
 }
@@ -97,3 +107,13 @@ func ErrorIllegalRange(e Error) Error {
 			"to shift the time by one day: <23:00-6:00 or 18:00-0:30>",
 	)
 }
+
+func ErrorMissingTemplateValue(e Error) Error {
+	return e.Set(
+		"ErrorMissingTemplateValue",
+		"Missing template value",
+		"There is no value for this template variable. "+
+			"Please pass it via --var NAME=VALUE, "+
+			"or declare a default value in the template: {{ NAME = VALUE }}",
+	)
+}
@@ -0,0 +1,174 @@
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/parser"
+	"github.com/jotaen/klog/src/parser/parsing"
+	"io"
+	"sort"
+	"strings"
+)
+
+// recordInput is the structure of a record when reading it back from JSON.
+// All entry types are decoded into a RangeView, since that contains the
+// fields of all the other types.
+type recordInput struct {
+	RecordView
+	Entries []RangeView `json:"entries"`
+}
+
+// FromJson decodes records from the JSON structure that `ToJson` produces.
+// Fields that are computed (e.g. `total` of a record or `tags`) are ignored.
+// The errors refer to the position of the respective record in the JSON text.
+func FromJson(text string) ([]Record, parsing.Errors) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	var records []Record
+	var errs []parsing.Error
+	fail := func(offset int64, e error) ([]Record, parsing.Errors) {
+		return nil, parsing.NewErrors([]parsing.Error{
+			ErrorInvalidJson(newJsonError(text, offset), e),
+		})
+	}
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return fail(dec.InputOffset(), errors.New("The input must be a JSON object with a `records` property"))
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return fail(dec.InputOffset(), err)
+		}
+		if key != "records" {
+			var ignore json.RawMessage
+			if err := dec.Decode(&ignore); err != nil {
+				return fail(dec.InputOffset(), err)
+			}
+			continue
+		}
+		if t, err := dec.Token(); err != nil || t != json.Delim('[') {
+			return fail(dec.InputOffset(), errors.New("The `records` property must be an array"))
+		}
+		for dec.More() {
+			offset := dec.InputOffset()
+			var input recordInput
+			if err := dec.Decode(&input); err != nil {
+				return fail(offset, err)
+			}
+			r, rErr := toRecord(input, func() parsing.Error { return newJsonError(text, offset) })
+			if rErr != nil {
+				errs = append(errs, rErr)
+				continue
+			}
+			records = append(records, r)
+		}
+		if _, err := dec.Token(); err != nil {
+			return fail(dec.InputOffset(), err)
+		}
+	}
+	if _, err := dec.Token(); err != nil && err != io.EOF {
+		return fail(dec.InputOffset(), err)
+	}
+	if len(errs) > 0 {
+		return nil, parsing.NewErrors(errs)
+	}
+	return records, nil
+}
+
+func toRecord(input recordInput, newError func() parsing.Error) (Record, parsing.Error) {
+	date, err := NewDateFromString(input.Date)
+	if err != nil {
+		return nil, parser.ErrorInvalidDate(newError())
+	}
+	r := NewRecord(date)
+	if input.ShouldTotal != "" {
+		should, err := NewDurationFromString(strings.TrimSuffix(input.ShouldTotal, "!"))
+		if err != nil {
+			return nil, parser.ErrorMalformedShouldTotal(newError())
+		}
+		if strings.HasSuffix(input.ShouldTotal, "!") || should.InMinutes() != 0 {
+			r.SetShouldTotal(should)
+		}
+	}
+	if err := r.SetSummary(input.Summary); err != nil {
+		return nil, parser.ErrorMalformedSummary(newError())
+	}
+	var keys []string
+	for k := range input.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p, err := NewProperty(k, input.Properties[k])
+		if err != nil {
+			return nil, parser.ErrorUnrecognisedProperty(newError())
+		}
+		r.SetProperty(p)
+	}
+	for _, e := range input.Entries {
+		summary := Summary(e.Summary)
+		switch e.Type {
+		case "duration":
+			d, err := NewDurationFromString(e.Total)
+			if err != nil {
+				return nil, parser.ErrorMalformedEntry(newError())
+			}
+			r.AddDuration(d, summary)
+		case "range":
+			start, sErr := NewTimeFromString(e.Start)
+			end, eErr := NewTimeFromString(e.End)
+			if sErr != nil || eErr != nil {
+				return nil, parser.ErrorMalformedEntry(newError())
+			}
+			tr, err := NewRange(start, end)
+			if err != nil {
+				return nil, parser.ErrorIllegalRange(newError())
+			}
+			r.AddRange(tr, summary)
+		case "open_range":
+			start, err := NewTimeFromString(e.Start)
+			if err != nil {
+				return nil, parser.ErrorMalformedEntry(newError())
+			}
+			if err := r.StartOpenRange(start, summary); err != nil {
+				return nil, parser.ErrorDuplicateOpenRange(newError())
+			}
+		default:
+			return nil, parser.ErrorMalformedEntry(newError())
+		}
+	}
+	if input.Comments != nil {
+		for _, c := range *input.Comments {
+			r.AddComment(c)
+		}
+	}
+	return r, nil
+}
+
+// newJsonError creates an error at the given offset of the JSON text. Any
+// whitespace or separators in front of the offset are skipped.
+func newJsonError(text string, offset int64) parsing.Error {
+	i := int(offset)
+	for i < len(text) && strings.ContainsRune(" \t\r\n,:", rune(text[i])) {
+		i++
+	}
+	lineStart := strings.LastIndex(text[:i], "\n") + 1
+	lineEnd := strings.Index(text[i:], "\n")
+	if lineEnd == -1 {
+		lineEnd = len(text)
+	} else {
+		lineEnd += i
+	}
+	return parsing.NewError(parsing.Line{
+		Text:       text[lineStart:lineEnd],
+		LineNumber: strings.Count(text[:i], "\n") + 1,
+	}, i-lineStart, 1)
+}
+
+func ErrorInvalidJson(e parsing.Error, cause error) parsing.Error {
+	return e.Set(
+		"ErrorInvalidJson",
+		"Invalid JSON",
+		cause.Error(),
+	)
+}
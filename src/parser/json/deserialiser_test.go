@@ -0,0 +1,86 @@
+package json
+
+import (
+	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDeserialiseRoundTrip(t *testing.T) {
+	text := `// Invoice sent
+2000-12-31 (7h30m!, billable, @office)
+Hello #World
+    2h3m #some #thing
+    <23:44 - 5:23
+        overnight
+    -30m
+    0:28> - ? Started #todo
+
+2001-01-01
+`
+	pr, errs := parser.Parse(text)
+	require.Nil(t, errs)
+	for _, pretty := range []bool{true, false} {
+		records, errs := FromJson(ToJson(pr.Records, nil, pretty, true))
+		require.Nil(t, errs)
+		assert.Equal(t, text, parser.PlainSerialiser.SerialiseRecordsWithComments(records...))
+	}
+}
+
+func TestDeserialiseMinimalRecord(t *testing.T) {
+	records, errs := FromJson(`{"records":[{"date":"2000-12-31","entries":[{"type":"duration","total":"1h"}]}]}`)
+	require.Nil(t, errs)
+	require.Len(t, records, 1)
+	assert.Equal(t, Ɀ_Date_(2000, 12, 31), records[0].Date())
+	assert.False(t, records[0].HasShouldTotal())
+	assert.Equal(t, NewDuration(1, 0), records[0].Entries()[0].Duration())
+}
+
+func TestDeserialiseEmptyRecords(t *testing.T) {
+	for _, text := range []string{`{}`, `{"records":[],"errors":null}`} {
+		records, errs := FromJson(text)
+		require.Nil(t, errs, text)
+		assert.Len(t, records, 0, text)
+	}
+}
+
+func TestDeserialiseReportsInvalidRecords(t *testing.T) {
+	records, errs := FromJson(`{"records": [
+  {"date": "2000-12-31"},
+  {"date": "2000-13-01"},
+  {"date": "2001-01-01", "entries": [{"type": "range", "start": "10:00", "end": "9:00"}]},
+  {"date": "2001-01-02", "entries": [{"type": "something"}]}
+]}`)
+	require.Nil(t, records)
+	require.NotNil(t, errs)
+	require.Len(t, errs.Get(), 3)
+	for i, e := range []struct {
+		line  int
+		title string
+	}{
+		{3, "Invalid date"},
+		{4, "Invalid date range"},
+		{5, "Malformed entry"},
+	} {
+		assert.Equal(t, e.line, errs.Get()[i].Context().LineNumber)
+		assert.Equal(t, 3, errs.Get()[i].Column())
+		assert.Equal(t, e.title, errs.Get()[i].Title())
+	}
+}
+
+func TestDeserialiseReportsInvalidJson(t *testing.T) {
+	for _, text := range []string{
+		``,
+		`[]`,
+		`{"records": 1}`,
+		`{"records": [{"date": 1}]}`,
+		`{"records": [{]}`,
+	} {
+		records, errs := FromJson(text)
+		require.Nil(t, records, text)
+		require.NotNil(t, errs, text)
+		assert.Equal(t, "Invalid JSON", errs.Get()[0].Title(), text)
+	}
+}
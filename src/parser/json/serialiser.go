@@ -9,11 +9,13 @@ import (
 	"strings"
 )
 
-func ToJson(rs []Record, errs parsing.Errors, prettyPrint bool) string {
+// ToJson serialises records (or errors) into JSON. The comments of the records
+// are only included if requested.
+func ToJson(rs []Record, errs parsing.Errors, prettyPrint bool, withComments bool) string {
 	envelop := func() Envelop {
 		if errs == nil {
 			return Envelop{
-				Records: toRecordViews(rs),
+				Records: toRecordViews(rs, withComments),
 				Errors:  nil,
 			}
 		} else {
@@ -36,7 +38,7 @@ func ToJson(rs []Record, errs parsing.Errors, prettyPrint bool) string {
 	return strings.TrimRight(buffer.String(), "\n")
 }
 
-func toRecordViews(rs []Record) []RecordView {
+func toRecordViews(rs []Record, withComments bool) []RecordView {
 	result := []RecordView{}
 	for _, r := range rs {
 		total := service.Total(r)
@@ -52,13 +54,26 @@ func toRecordViews(rs []Record) []RecordView {
 			Diff:            diff.ToStringWithSign(),
 			DiffMins:        diff.InMinutes(),
 			Tags:            toTagViews(r.Summary().Tags()),
+			Properties:      toPropertyViews(r.Properties()),
 			Entries:         toEntryViews(r.Entries()),
 		}
+		if withComments {
+			comments := append([]string{}, r.Comments()...)
+			v.Comments = &comments
+		}
 		result = append(result, v)
 	}
 	return result
 }
 
+func toPropertyViews(ps []Property) map[string]string {
+	result := make(map[string]string, len(ps))
+	for _, p := range ps {
+		result[p.Key] = p.Value
+	}
+	return result
+}
+
 func toTagViews(ts TagSet) []string {
 	result := ts.ToStrings()
 	if result == nil {
@@ -9,17 +9,17 @@ import (
 )
 
 func TestSerialiseEmptyRecords(t *testing.T) {
-	json := ToJson([]Record{}, nil, false)
+	json := ToJson([]Record{}, nil, false, false)
 	assert.Equal(t, `{"records":[],"errors":null}`, json)
 }
 
 func TestSerialiseEmptyArrayIfNoErrors(t *testing.T) {
-	json := ToJson(nil, nil, false)
+	json := ToJson(nil, nil, false, false)
 	assert.Equal(t, `{"records":[],"errors":null}`, json)
 }
 
 func TestSerialisePrettyPrinted(t *testing.T) {
-	json := ToJson(nil, nil, true)
+	json := ToJson(nil, nil, true, false)
 	assert.Equal(t, `{
   "records": [],
   "errors": null
@@ -30,7 +30,7 @@ func TestSerialiseMinimalRecord(t *testing.T) {
 	json := ToJson(func() []Record {
 		r := NewRecord(Ɀ_Date_(2000, 12, 31))
 		return []Record{r}
-	}(), nil, false)
+	}(), nil, false, false)
 	assert.Equal(t, `{"records":[{`+
 		`"date":"2000-12-31",`+
 		`"summary":"",`+
@@ -41,6 +41,7 @@ func TestSerialiseMinimalRecord(t *testing.T) {
 		`"diff":"0m",`+
 		`"diff_mins":0,`+
 		`"tags":[],`+
+		`"properties":{},`+
 		`"entries":[]`+
 		`}],"errors":null}`, json)
 }
@@ -50,11 +51,13 @@ func TestSerialiseFullBlownRecord(t *testing.T) {
 		r := NewRecord(Ɀ_Date_(2000, 12, 31))
 		r.SetSummary("Hello #World")
 		r.SetShouldTotal(NewDuration(7, 30))
+		r.SetProperty(Property{Key: "location", Value: "office"})
+		r.SetProperty(Property{Key: "billable"})
 		r.AddDuration(NewDuration(2, 3), "#some #thing")
 		r.AddRange(Ɀ_Range_(Ɀ_TimeYesterday_(23, 44), Ɀ_Time_(5, 23)), "")
 		r.StartOpenRange(Ɀ_TimeTomorrow_(0, 28), "Started #todo")
 		return []Record{r}
-	}(), nil, false)
+	}(), nil, false, false)
 	assert.Equal(t, `{"records":[{`+
 		`"date":"2000-12-31",`+
 		`"summary":"Hello #World",`+
@@ -65,6 +68,7 @@ func TestSerialiseFullBlownRecord(t *testing.T) {
 		`"diff":"+12m",`+
 		`"diff_mins":12,`+
 		`"tags":["#world"],`+
+		`"properties":{"billable":"","location":"office"},`+
 		`"entries":[{`+
 		`"type":"duration",`+
 		`"summary":"#some #thing",`+
@@ -93,13 +97,23 @@ func TestSerialiseFullBlownRecord(t *testing.T) {
 		`}],"errors":null}`, json)
 }
 
+func TestSerialiseRecordWithComments(t *testing.T) {
+	json := ToJson(func() []Record {
+		r := NewRecord(Ɀ_Date_(2000, 12, 31))
+		r.AddComment("Invoice sent")
+		return []Record{r, NewRecord(Ɀ_Date_(2001, 1, 1))}
+	}(), nil, false, true)
+	assert.Contains(t, json, `"properties":{},"entries":[],"comments":["Invoice sent"]}`)
+	assert.Contains(t, json, `"properties":{},"entries":[],"comments":[]}`)
+}
+
 func TestSerialiseParserErrors(t *testing.T) {
 	json := ToJson(nil, parsing.NewErrors([]parsing.Error{
 		parser.ErrorInvalidDate(parsing.NewError(parsing.Line{
 			Text:       "2018-99-99",
 			LineNumber: 7,
 		}, 0, 10)),
-	}), false)
+	}), false, false)
 	assert.Equal(t, `{"records":null,"errors":[{`+
 		`"line":7,`+
 		`"column":1,`+
@@ -108,3 +122,9 @@ func TestSerialiseParserErrors(t *testing.T) {
 		`"details":"Please make sure that the date format is either YYYY-MM-DD or YYYY/MM/DD, and that its value represents a valid day in the calendar."`+
 		`}]}`, json)
 }
+
+func TestSerialiseEntrySummaryWithContinuationLines(t *testing.T) {
+	pr, _ := parser.Parse("2000-12-31\n    1h Foo\n        #bar\n")
+	json := ToJson(pr.Records, nil, false, false)
+	assert.Contains(t, json, `"summary":"Foo\n#bar","tags":["#bar"]`)
+}
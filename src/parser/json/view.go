@@ -6,16 +6,18 @@ type Envelop struct {
 }
 
 type RecordView struct {
-	Date            string        `json:"date"`
-	Summary         string        `json:"summary"`
-	Total           string        `json:"total"`
-	TotalMins       int           `json:"total_mins"`
-	ShouldTotal     string        `json:"should_total"`
-	ShouldTotalMins int           `json:"should_total_mins"`
-	Diff            string        `json:"diff"`
-	DiffMins        int           `json:"diff_mins"`
-	Tags            []string      `json:"tags"`
-	Entries         []interface{} `json:"entries"`
+	Date            string            `json:"date"`
+	Summary         string            `json:"summary"`
+	Total           string            `json:"total"`
+	TotalMins       int               `json:"total_mins"`
+	ShouldTotal     string            `json:"should_total"`
+	ShouldTotalMins int               `json:"should_total_mins"`
+	Diff            string            `json:"diff"`
+	DiffMins        int               `json:"diff_mins"`
+	Tags            []string          `json:"tags"`
+	Properties      map[string]string `json:"properties"`
+	Entries         []interface{}     `json:"entries"`
+	Comments        *[]string         `json:"comments,omitempty"`
 }
 
 type EntryView struct {
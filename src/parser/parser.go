@@ -6,33 +6,69 @@ import (
 )
 
 type ParseResult struct {
-	Records          []Record
-	lines            []Line
-	lastLineOfRecord []int
-	preferences      Preferences
+	Records           []Record
+	lines             []Line
+	firstLineOfRecord []int
+	lastLineOfRecord  []int
+	entryPositions    [][]entryPosition
+	preferences       Preferences
+}
+
+// entryPosition holds the line numbers of the first and of the last line of an
+// entry. They only differ if the entry summary has continuation lines.
+type entryPosition struct {
+	firstLine int
+	lastLine  int
 }
 
 // Parse parses a text with records into Record data structures.
 func Parse(recordsAsText string) (*ParseResult, Errors) {
 	parseResult := ParseResult{
-		Records:          nil,
-		lines:            Split(recordsAsText),
-		lastLineOfRecord: nil,
-		preferences:      DefaultPreferences(),
+		Records:           nil,
+		lines:             Split(recordsAsText),
+		firstLineOfRecord: nil,
+		lastLineOfRecord:  nil,
+		preferences:       DefaultPreferences(),
 	}
 	var allErrs []Error
 	blocks := GroupIntoBlocks(parseResult.lines)
 	for _, block := range blocks {
-		r, errs := parseRecord(block)
+		// Comments are taken out before parsing the record. Blocks that only
+		// consist of comments don’t belong to any record.
+		var recordLines []Line
+		var comments []string
+		for _, l := range block {
+			if IsComment(l) {
+				comments = append(comments, CommentText(l))
+				continue
+			}
+			recordLines = append(recordLines, l)
+		}
+		if len(recordLines) == 0 {
+			continue
+		}
+		r, positions, errs := parseRecord(recordLines)
 		if len(errs) > 0 {
 			allErrs = append(allErrs, errs...)
+			continue
 		}
+		for _, c := range comments {
+			r.AddComment(c)
+		}
+		parseResult.entryPositions = append(parseResult.entryPositions, positions)
 		parseResult.Records = append(parseResult.Records, r)
+		parseResult.firstLineOfRecord = append(
+			parseResult.firstLineOfRecord,
+			block[0].LineNumber,
+		)
 		parseResult.lastLineOfRecord = append(
 			parseResult.lastLineOfRecord,
 			block[len(block)-1].LineNumber,
 		)
 		for _, l := range block {
+			if isContinuationLine(positions, l) {
+				continue
+			}
 			parseResult.preferences.Adapt(&l)
 		}
 	}
@@ -42,7 +78,50 @@ func Parse(recordsAsText string) (*ParseResult, Errors) {
 	return &parseResult, nil
 }
 
-func parseRecord(block []Line) (Record, []Error) {
+// Empty returns a result without any records, which has the same formatting
+// preferences (e.g. the indentation style) as the original.
+func (pr *ParseResult) Empty() *ParseResult {
+	empty, _ := Parse("")
+	empty.preferences = pr.preferences
+	return empty
+}
+
+// Text returns the text that the result is based on.
+func (pr *ParseResult) Text() string {
+	return Join(pr.lines)
+}
+
+// LinesOfRecord returns the line numbers of the headline and of the last line
+// of the record at the given index. (Comments above the headline are skipped.)
+func (pr *ParseResult) LinesOfRecord(i int) (int, int) {
+	first := pr.firstLineOfRecord[i]
+	for IsComment(pr.lines[first-1]) {
+		first++
+	}
+	return first, pr.lastLineOfRecord[i]
+}
+
+// indentationLevel returns the indentation level of a line. Continuation lines
+// of entry summaries are nested one level deeper than their entry.
+func (pr *ParseResult) indentationLevel(l Line) int {
+	for _, positions := range pr.entryPositions {
+		if isContinuationLine(positions, l) {
+			return 2
+		}
+	}
+	return l.IndentationLevel()
+}
+
+func isContinuationLine(positions []entryPosition, l Line) bool {
+	for _, p := range positions {
+		if l.LineNumber > p.firstLine && l.LineNumber <= p.lastLine {
+			return !IsComment(l)
+		}
+	}
+	return false
+}
+
+func parseRecord(block []Line) (Record, []entryPosition, []Error) {
 	var errs []Error
 
 	// ========== HEADLINE ==========
@@ -68,28 +147,46 @@ func parseRecord(block []Line) (Record, []Error) {
 				errs = append(errs, ErrorMalformedPropertiesSyntax(NewError(headline.Line, headline.Length(), 1)))
 				return r
 			}
-			if allPropsText.Length() == 0 {
+			props := splitProperties(allPropsText)
+			if len(props) == 0 {
 				errs = append(errs, ErrorMalformedPropertiesSyntax(NewError(headline.Line, headline.PointerPosition, 1)))
 				return r
 			}
-			shouldTotalText, hasExclamationMark := headline.PeekUntil(func(r rune) bool { return r == '!' })
-			if !hasExclamationMark {
-				errs = append(errs, ErrorUnrecognisedProperty(NewError(headline.Line, headline.PointerPosition, shouldTotalText.Length()-1)))
-				return r
-			}
-			shouldTotal, err := NewDurationFromString(shouldTotalText.ToString())
-			if err != nil {
-				errs = append(errs, ErrorMalformedShouldTotal(NewError(headline.Line, headline.PointerPosition, shouldTotalText.Length())))
-				return r
-			}
-			r.SetShouldTotal(shouldTotal)
-			headline.Advance(shouldTotalText.Length())
-			headline.Advance(1) // '!'
-			headline.SkipWhitespace()
-			if headline.Peek() != ')' {
-				errs = append(errs, ErrorUnrecognisedProperty(NewError(headline.Line, headline.PointerPosition, headline.RemainingLength()-1)))
-				return r
+			for _, prop := range props {
+				shouldTotalText, hasExclamationMark := prop.PeekUntil(func(r rune) bool { return r == '!' })
+				if hasExclamationMark {
+					if r.HasShouldTotal() {
+						errs = append(errs, ErrorDuplicateProperty(NewError(headline.Line, prop.position, prop.Length())))
+						return r
+					}
+					shouldTotal, err := NewDurationFromString(shouldTotalText.ToString())
+					if err != nil {
+						errs = append(errs, ErrorMalformedShouldTotal(NewError(headline.Line, prop.position, shouldTotalText.Length())))
+						return r
+					}
+					if shouldTotalText.Length()+1 < prop.Length() {
+						errs = append(errs, ErrorUnrecognisedProperty(NewError(
+							headline.Line,
+							prop.position+shouldTotalText.Length()+1,
+							prop.Length()-shouldTotalText.Length()-1,
+						)))
+						return r
+					}
+					r.SetShouldTotal(shouldTotal)
+					continue
+				}
+				property, err := NewPropertyFromString(prop.ToString())
+				if err != nil {
+					errs = append(errs, ErrorUnrecognisedProperty(NewError(headline.Line, prop.position, prop.Length())))
+					return r
+				}
+				if _, exists := r.Property(property.Key); exists {
+					errs = append(errs, ErrorDuplicateProperty(NewError(headline.Line, prop.position, prop.Length())))
+					return r
+				}
+				r.SetProperty(property)
 			}
+			headline.Advance(allPropsText.Length())
 			headline.Advance(1) // ')'
 		}
 		headline.SkipWhitespace()
@@ -127,8 +224,22 @@ func parseRecord(block []Line) (Record, []Error) {
 	}
 
 	// ========== ENTRIES ==========
+	// Lines that are indented deeper than the preceding entry continue the
+	// summary of that entry, unless they look like an entry themselves. (In
+	// that case, the entry is reported as being wrongly indented.)
+	var groups [][]Line
+	for _, l := range block {
+		if len(groups) > 0 && l.IndentationWidth() > groups[len(groups)-1][0].IndentationWidth() && !startsWithEntryValue(l) {
+			groups[len(groups)-1] = append(groups[len(groups)-1], l)
+			continue
+		}
+		groups = append(groups, []Line{l})
+	}
+	var positions []entryPosition
 entries:
-	for _, e := range block {
+	for _, group := range groups {
+		e := group[0]
+		positions = append(positions, entryPosition{e.LineNumber, group[len(group)-1].LineNumber})
 		entry := NewParseable(e)
 		if entry.IndentationLevel() != 1 {
 			errs = append(errs, ErrorIllegalIndentation(NewError(entry.Line, 0, entry.Length())))
@@ -140,7 +251,7 @@ entries:
 			entry.Advance(durationCandidate.Length())
 			entry.SkipWhitespace()
 			summaryText, _ := entry.PeekUntil(func(r rune) bool { return false })
-			record.AddDuration(duration, Summary(summaryText.ToString()))
+			record.AddDuration(duration, entrySummary(summaryText, group[1:]))
 			continue
 		}
 		startCandidate, _ := entry.PeekUntil(func(r rune) bool { return r == '-' || IsWhitespace(r) })
@@ -175,7 +286,7 @@ entries:
 			entry.Advance(placeholder.Length())
 			entry.SkipWhitespace()
 			summaryText, _ := entry.PeekUntil(func(r rune) bool { return false })
-			err := record.StartOpenRange(start, Summary(summaryText.ToString()))
+			err := record.StartOpenRange(start, entrySummary(summaryText, group[1:]))
 			if err != nil {
 				errs = append(errs, ErrorDuplicateOpenRange(NewError(entry.Line, 0, entry.PointerPosition)))
 				continue
@@ -199,12 +310,58 @@ entries:
 			}
 			entry.SkipWhitespace()
 			summaryText, _ := entry.PeekUntil(func(r rune) bool { return false })
-			record.AddRange(timeRange, Summary(summaryText.ToString()))
+			record.AddRange(timeRange, entrySummary(summaryText, group[1:]))
 		}
 	}
 
 	if len(errs) > 0 {
-		return nil, errs
+		return nil, nil, errs
+	}
+	return record, positions, nil
+}
+
+type headlineProperty struct {
+	Parseable
+	position int // The position of the property within the headline
+}
+
+// splitProperties splits the text of the properties block into the individual
+// properties, which are separated by commas or whitespace.
+func splitProperties(allPropsText Parseable) []headlineProperty {
+	var props []headlineProperty
+	var current *headlineProperty
+	for i, c := range allPropsText.Chars {
+		if c == ',' || IsWhitespace(c) {
+			current = nil
+			continue
+		}
+		if current == nil {
+			props = append(props, headlineProperty{position: allPropsText.PointerPosition + i})
+			current = &props[len(props)-1]
+		}
+		current.Chars = append(current.Chars, c)
+	}
+	return props
+}
+
+// startsWithEntryValue checks whether a line starts with a duration or a time,
+// i.e. with what the value of an entry would start with.
+func startsWithEntryValue(l Line) bool {
+	p := NewParseable(l)
+	p.SkipWhitespace()
+	durationCandidate, _ := p.PeekUntil(func(r rune) bool { return IsWhitespace(r) })
+	if _, err := NewDurationFromString(durationCandidate.ToString()); err == nil {
+		return true
+	}
+	timeCandidate, _ := p.PeekUntil(func(r rune) bool { return r == '-' || IsWhitespace(r) })
+	_, err := NewTimeFromString(timeCandidate.ToString())
+	return err == nil
+}
+
+func entrySummary(firstLine Parseable, continuationLines []Line) Summary {
+	summary := firstLine.ToString()
+	for _, l := range continuationLines {
+		summary += "\n" + l.Text
 	}
-	return record, nil
+	return Summary(summary)
 }
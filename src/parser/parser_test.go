@@ -36,6 +36,33 @@ Empty
 	assert.Len(t, pr.Records[1].Entries(), 0)
 }
 
+func TestParseRecordProperties(t *testing.T) {
+	for _, text := range []string{
+		"2020-01-01 (8h!, @office, billable, tz=Europe/Berlin)",
+		"2020-01-01 (  8h!  @office,billable   tz=Europe/Berlin )",
+	} {
+		pr, errs := Parse(text)
+		require.Nil(t, errs, text)
+		require.Len(t, pr.Records, 1, text)
+		r := pr.Records[0]
+		assert.Equal(t, 8*60, r.ShouldTotal().InMinutes(), text)
+		assert.Equal(t, []klog.Property{
+			{Key: "location", Value: "office"},
+			{Key: "billable", Value: ""},
+			{Key: "tz", Value: "Europe/Berlin"},
+		}, r.Properties(), text)
+	}
+}
+
+func TestParseRecordPropertiesWithoutShouldTotal(t *testing.T) {
+	pr, errs := Parse("2020-01-01 (billable)")
+	require.Nil(t, errs)
+	assert.False(t, pr.Records[0].HasShouldTotal())
+	p, ok := pr.Records[0].Property("billable")
+	assert.True(t, ok)
+	assert.True(t, p.IsFlag())
+}
+
 func TestParseEmptyOrBlankDocument(t *testing.T) {
 	for _, text := range []string{
 		"",
@@ -130,6 +157,72 @@ func TestParseDocumentSucceedsWithCorrectEntries(t *testing.T) {
 	}
 }
 
+func TestParseComments(t *testing.T) {
+	text := `
+// About the first record
+1999-05-31
+// Inside of the record
+Summary
+	// Indented
+	5h30m This and that
+	2h
+
+// Standalone comment
+
+1999-06-01
+	1h
+// At the end
+`
+	pr, errs := Parse(text)
+	require.Nil(t, errs)
+	require.Len(t, pr.Records, 2)
+
+	assert.Equal(t, klog.Summary("Summary"), pr.Records[0].Summary())
+	assert.Len(t, pr.Records[0].Entries(), 2)
+	assert.Equal(t, []string{"About the first record", "Inside of the record", "Indented"}, pr.Records[0].Comments())
+
+	assert.Len(t, pr.Records[1].Entries(), 1)
+	assert.Equal(t, []string{"At the end"}, pr.Records[1].Comments())
+}
+
+func TestParseSummariesThatStartWithCommentPrefix(t *testing.T) {
+	text := `
+1999-05-31
+//server maintenance
+	5h30m Deploy
+		//cluster-a
+	//
+`
+	pr, errs := Parse(text)
+	require.Nil(t, errs)
+	require.Len(t, pr.Records, 1)
+	assert.Equal(t, klog.Summary("//server maintenance"), pr.Records[0].Summary())
+	assert.Equal(t, klog.Summary("Deploy\n//cluster-a"), pr.Records[0].Entries()[0].Summary())
+	assert.Equal(t, []string{""}, pr.Records[0].Comments())
+}
+
+func TestParseEntrySummaryWithContinuationLines(t *testing.T) {
+	text := `
+2020-01-01
+	1h Meeting with #clients
+		Discussed the #roadmap
+		and the budget
+	8:00-9:00
+	  Preparation
+	  // Not part of the summary
+	2h
+`
+	pr, errs := Parse(text)
+	require.Nil(t, errs)
+	require.Len(t, pr.Records, 1)
+	es := pr.Records[0].Entries()
+	require.Len(t, es, 3)
+	assert.Equal(t, klog.Summary("Meeting with #clients\nDiscussed the #roadmap\nand the budget"), es[0].Summary())
+	assert.Equal(t, klog.NewTagSet("clients", "roadmap"), es[0].Summary().Tags())
+	assert.Equal(t, klog.Summary("\nPreparation"), es[1].Summary())
+	assert.Equal(t, klog.Summary(""), es[2].Summary())
+}
+
 func TestMalformedRecord(t *testing.T) {
 	text := `
 1999-05-31
@@ -153,6 +246,8 @@ func TestReportErrorsInHeadline(t *testing.T) {
 		{"   2020-01-01", Err{id(ErrorIllegalIndentation), 1, 0, 10}},
 		{"2020-01-01 ()", Err{id(ErrorMalformedPropertiesSyntax), 1, 12, 1}},
 		{"2020-01-01 (asdf)", Err{id(ErrorUnrecognisedProperty), 1, 12, 4}},
+		{"2020-01-01 (8h)", Err{id(ErrorUnrecognisedProperty), 1, 12, 2}},
+		{"2020-01-01 (8h=x)", Err{id(ErrorUnrecognisedProperty), 1, 12, 4}},
 		{"2020-01-01 (asdf!)", Err{id(ErrorMalformedShouldTotal), 1, 12, 4}},
 		{"2020-01-01 5h30m!", Err{id(ErrorUnrecognisedTextInHeadline), 1, 11, 6}},
 		{"2020-01-01 (5h30m!", Err{id(ErrorMalformedPropertiesSyntax), 1, 18, 1}},
@@ -160,6 +255,10 @@ func TestReportErrorsInHeadline(t *testing.T) {
 		{"2020-01-01 (5h!) foo", Err{id(ErrorUnrecognisedTextInHeadline), 1, 17, 3}},
 		{"2020-01-01 (5h! asdf)", Err{id(ErrorUnrecognisedProperty), 1, 16, 4}},
 		{"2020-01-01 (5h!!!)", Err{id(ErrorUnrecognisedProperty), 1, 15, 2}},
+		{"2020-01-01 (, )", Err{id(ErrorMalformedPropertiesSyntax), 1, 12, 1}},
+		{"2020-01-01 (5h!, 6h!)", Err{id(ErrorDuplicateProperty), 1, 17, 3}},
+		{"2020-01-01 (@home, billable, @office)", Err{id(ErrorDuplicateProperty), 1, 29, 7}},
+		{"2020-01-01 (tz=)", Err{id(ErrorUnrecognisedProperty), 1, 12, 3}},
 	} {
 		pr, errs := Parse(test.text)
 		require.Nil(t, pr)
@@ -202,6 +301,9 @@ func TestReportErrorsInEntries(t *testing.T) {
 		{"2020-01-01\n\t 8h", Err{id(ErrorIllegalIndentation), 2, 0, 2}},
 		{"2020-01-01\n\t\t8h", Err{id(ErrorIllegalIndentation), 2, 0, 2}},
 		{"2020-01-01\n     8h", Err{id(ErrorIllegalIndentation), 2, 0, 2}},
+		{"2020-01-01\n\t1h\n\t\t2h", Err{id(ErrorIllegalIndentation), 3, 0, 2}},
+		{"2020-01-01\n    1h\n        2h", Err{id(ErrorIllegalIndentation), 3, 0, 2}},
+		{"2020-01-01\n\t1h Foo\n\t\t8:00 - 9:00", Err{id(ErrorIllegalIndentation), 3, 0, 11}},
 	} {
 		pr, errs := Parse(test.text)
 		require.Nil(t, pr, test.text)
@@ -30,16 +30,22 @@ func (l *Line) Original() string {
 }
 
 func (l *Line) IndentationLevel() int {
-	normalised := strings.ReplaceAll(l.originalIndentation, "\t", "    ")
-	if normalised == "" {
+	width := l.IndentationWidth()
+	if width == 0 {
 		return 0
 	}
-	if len(normalised) == 1 || len(normalised) > 4 {
+	if width == 1 || width > 4 {
 		return -1
 	}
 	return 1
 }
 
+// IndentationWidth returns the number of spaces that the line is indented by,
+// where a tab counts as four spaces.
+func (l *Line) IndentationWidth() int {
+	return len(strings.ReplaceAll(l.originalIndentation, "\t", "    "))
+}
+
 func Split(text string) []Line {
 	var result []Line
 	remainder := text
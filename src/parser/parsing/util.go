@@ -1,5 +1,9 @@
 package parsing
 
+import "strings"
+
+const COMMENT_PREFIX = "//"
+
 func SubRune(text []rune, start int, length int) []rune {
 	if start >= len(text) {
 		return nil
@@ -45,6 +49,22 @@ func IsBlank(l Line) bool {
 	return true
 }
 
+// IsComment checks whether a line is a comment, regardless of its indentation.
+// The prefix must be followed by whitespace (or nothing), so that summaries
+// which happen to start with `//` (e.g. `//server`) don’t become comments.
+func IsComment(l Line) bool {
+	if !strings.HasPrefix(l.Text, COMMENT_PREFIX) {
+		return false
+	}
+	rest := strings.TrimPrefix(l.Text, COMMENT_PREFIX)
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+}
+
+// CommentText returns the text of a comment line, without the comment prefix.
+func CommentText(l Line) string {
+	return strings.TrimSpace(strings.TrimPrefix(l.Text, COMMENT_PREFIX))
+}
+
 type Text struct {
 	Text        string
 	Indentation int
@@ -59,7 +79,7 @@ func Insert(ls []Line, position int, texts []Text, prefs Preferences) []Line {
 	for i := range result {
 		if i >= position && offset < len(texts) {
 			line := ""
-			if texts[offset].Indentation > 0 {
+			for j := 0; j < texts[offset].Indentation; j++ {
 				line += prefs.Indentation
 			}
 			line += texts[offset].Text + prefs.LineEnding
@@ -75,3 +95,16 @@ func Insert(ls []Line, position int, texts []Text, prefs Preferences) []Line {
 	}
 	return result
 }
+
+func Remove(ls []Line, position int, count int) []Line {
+	if position < 0 || position+count > len(ls) {
+		panic("Out of bounds")
+	}
+	result := make([]Line, 0, len(ls)-count)
+	result = append(result, ls[:position]...)
+	result = append(result, ls[position+count:]...)
+	for i := range result {
+		result[i].LineNumber = i + 1
+	}
+	return result
+}
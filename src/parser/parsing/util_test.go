@@ -100,3 +100,20 @@ func TestInsertIntoEmptySlice(t *testing.T) {
 	require.Len(t, after, 1)
 	assert.Equal(t, "Hello World\n", after[0].Original())
 }
+
+func TestRemoveLines(t *testing.T) {
+	before := Split("first\nsecond\nthird\nfourth\n")
+	after := Remove(before, 1, 2)
+	require.Len(t, after, 2)
+	assert.Equal(t, "first\n", after[0].Original())
+	assert.Equal(t, 1, after[0].LineNumber)
+	assert.Equal(t, "fourth\n", after[1].Original())
+	assert.Equal(t, 2, after[1].LineNumber)
+}
+
+func TestRemoveLastLine(t *testing.T) {
+	before := Split("first\nsecond")
+	after := Remove(before, 1, 1)
+	require.Len(t, after, 1)
+	assert.Equal(t, "first\n", after[0].Original())
+}
@@ -5,6 +5,8 @@ import (
 	. "github.com/jotaen/klog/src"
 	"github.com/jotaen/klog/src/parser/parsing"
 	"regexp"
+	"strings"
+	"unicode"
 )
 
 type ReconcileResult struct {
@@ -22,6 +24,15 @@ type BlockReconciler struct {
 	maybeRecordPointer int
 }
 
+type RemovalReconciler struct {
+	pr             *ParseResult
+	recordPointers []int
+}
+
+type FormattingReconciler struct {
+	pr *ParseResult
+}
+
 func NewRecordReconciler(pr *ParseResult, matchRecord func(Record) bool) *RecordReconciler {
 	index := -1
 	for i, r := range pr.Records {
@@ -40,11 +51,11 @@ func NewRecordReconciler(pr *ParseResult, matchRecord func(Record) bool) *Record
 }
 
 func (r *RecordReconciler) AppendEntry(handler func(Record) string) (*ReconcileResult, error) {
-	newEntry := handler(r.pr.Records[r.recordPointer])
+	newEntry := strings.Split(handler(r.pr.Records[r.recordPointer]), "\n")
 	result := parsing.Insert(
 		r.pr.lines,
 		r.pr.lastLineOfRecord[r.recordPointer],
-		[]parsing.Text{{newEntry, 1}},
+		append([]parsing.Text{{newEntry[0], 1}}, continuationTexts(newEntry[1:])...),
 		r.pr.preferences,
 	)
 	return makeResult(result, r.recordPointer)
@@ -67,19 +78,93 @@ func (r *RecordReconciler) CloseOpenRange(handler func(Record) (Time, Summary))
 		)
 	}
 	time, summary := handler(record)
-	openRangeLineIndex := r.pr.lastLineOfRecord[r.recordPointer] - len(record.Entries()) + entryIndex
+	openRangeLineIndex, lastLineIndex := r.entryLineIndices(entryIndex)
 	originalText := r.pr.lines[openRangeLineIndex].Text
-	summaryText := func() string {
-		if summary.ToString() == "" {
-			return summary.ToString()
-		}
-		return " " + summary.ToString()
-	}()
 	r.pr.lines[openRangeLineIndex].Text = regexp.MustCompile(`^(.*?)\?+(.*)$`).
-		ReplaceAllString(originalText, "${1}"+time.ToString()+"${2}"+summaryText)
+		ReplaceAllString(originalText, "${1}"+time.ToString()+"${2}")
+	// The summary is appended to the end, which might be a continuation line.
+	if summary.ToString() != "" {
+		r.pr.lines[lastLineIndex].Text += " " + summary.ToString()
+	}
 	return makeResult(r.pr.lines, r.recordPointer)
 }
 
+// UpdateEntry replaces the first entry that matches. The handler receives the
+// original text of the time value along with the summary, and it returns the
+// replacements for these. Continuation lines of the summary are replaced as well.
+func (r *RecordReconciler) UpdateEntry(
+	matchEntry func(int, Entry) bool,
+	handler func(string, Summary) (string, Summary),
+) (*ReconcileResult, error) {
+	entryIndex, err := r.findEntry(matchEntry)
+	if err != nil {
+		return nil, err
+	}
+	entry := r.pr.Records[r.recordPointer].Entries()[entryIndex]
+	lineIndex, lastLineIndex := r.entryLineIndices(entryIndex)
+	originalText := r.pr.lines[lineIndex].Text
+	originalSummaryLines := strings.Split(entry.Summary().ToString(), "\n")
+	originalValue := strings.TrimRightFunc(
+		strings.TrimSuffix(originalText, originalSummaryLines[0]),
+		unicode.IsSpace,
+	)
+	value, summary := handler(originalValue, entry.Summary())
+	summaryLines := strings.Split(summary.ToString(), "\n")
+	newText := value
+	if summaryLines[0] != "" {
+		newText += " " + summaryLines[0]
+	}
+	r.pr.lines[lineIndex].Text = newText
+	lines := removeExceptComments(r.pr.lines, lineIndex+1, lastLineIndex)
+	lines = parsing.Insert(lines, lineIndex+1, continuationTexts(summaryLines[1:]), r.pr.preferences)
+	return makeResult(lines, r.recordPointer)
+}
+
+// RemoveEntry deletes the first entry that matches.
+func (r *RecordReconciler) RemoveEntry(matchEntry func(int, Entry) bool) (*ReconcileResult, error) {
+	entryIndex, err := r.findEntry(matchEntry)
+	if err != nil {
+		return nil, err
+	}
+	first, last := r.entryLineIndices(entryIndex)
+	lines := removeExceptComments(r.pr.lines, first, last)
+	return makeResult(lines, r.recordPointer)
+}
+
+// removeExceptComments removes the lines between the two indices (inclusive),
+// except for comments, which might be interspersed with continuation lines.
+func removeExceptComments(ls []parsing.Line, first int, last int) []parsing.Line {
+	for i := last; i >= first; i-- {
+		if !parsing.IsComment(ls[i]) {
+			ls = parsing.Remove(ls, i, 1)
+		}
+	}
+	return ls
+}
+
+func (r *RecordReconciler) findEntry(matchEntry func(int, Entry) bool) (int, error) {
+	for i, e := range r.pr.Records[r.recordPointer].Entries() {
+		if matchEntry(i, e) {
+			return i, nil
+		}
+	}
+	return -1, errors.New("No matching entry found")
+}
+
+// entryLineIndices returns the indices of the first and of the last line of an entry.
+func (r *RecordReconciler) entryLineIndices(entryIndex int) (int, int) {
+	p := r.pr.entryPositions[r.recordPointer][entryIndex]
+	return p.firstLine - 1, p.lastLine - 1
+}
+
+func continuationTexts(summaryLines []string) []parsing.Text {
+	var texts []parsing.Text
+	for _, l := range summaryLines {
+		texts = append(texts, parsing.Text{l, 2})
+	}
+	return texts
+}
+
 func NewBlockReconciler(pr *ParseResult, newDate Date) *BlockReconciler {
 	index := -1
 	for i, r := range pr.Records {
@@ -124,15 +209,126 @@ func (r *BlockReconciler) InsertBlock(texts []parsing.Text) (*ReconcileResult, e
 	return makeResult(lines, newRecordIndex)
 }
 
+func NewRemovalReconciler(pr *ParseResult, matchRecord func(Record) bool) *RemovalReconciler {
+	var indices []int
+	for i, r := range pr.Records {
+		if matchRecord(r) {
+			indices = append(indices, i)
+		}
+	}
+	if len(indices) == 0 {
+		return nil
+	}
+	return &RemovalReconciler{
+		pr:             pr,
+		recordPointers: indices,
+	}
+}
+
+// Records returns the records that are subject to removal.
+func (r *RemovalReconciler) Records() []Record {
+	var result []Record
+	for _, i := range r.recordPointers {
+		result = append(result, r.pr.Records[i])
+	}
+	return result
+}
+
+// Blocks returns the original lines of the records that are subject to removal.
+func (r *RemovalReconciler) Blocks() [][]parsing.Text {
+	var result [][]parsing.Text
+	for _, p := range r.recordPointers {
+		var block []parsing.Text
+		for _, l := range r.pr.lines[r.pr.firstLineOfRecord[p]-1 : r.pr.lastLineOfRecord[p]] {
+			block = append(block, parsing.Text{l.Text, r.pr.indentationLevel(l)})
+		}
+		result = append(result, block)
+	}
+	return result
+}
+
+// RemoveRecords deletes the entire blocks of the records. The blank lines
+// that had separated a block from its neighbours are removed as well.
+// The resulting `NewRecord` is always `nil`.
+func (r *RemovalReconciler) RemoveRecords() (*ReconcileResult, error) {
+	lines := r.pr.lines
+	// Go backwards, so that the line indices of the preceding records remain valid.
+	for i := len(r.recordPointers) - 1; i >= 0; i-- {
+		p := r.recordPointers[i]
+		start := r.pr.firstLineOfRecord[p] - 1
+		end := r.pr.lastLineOfRecord[p] - 1
+		next := end + 1
+		for next < len(lines) && parsing.IsBlank(lines[next]) {
+			next++
+		}
+		if next < len(lines) {
+			end = next - 1
+		} else {
+			for start > 0 && parsing.IsBlank(lines[start-1]) {
+				start--
+			}
+		}
+		lines = parsing.Remove(lines, start, end-start+1)
+	}
+	newText, _, err := validate(lines)
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileResult{
+		nil,
+		newText,
+	}, nil
+}
+
+func NewFormattingReconciler(pr *ParseResult) *FormattingReconciler {
+	return &FormattingReconciler{pr}
+}
+
+// Format normalises the whitespace in the file: the indentation is made
+// uniform, trailing whitespace is removed, and there is exactly one blank
+// line between blocks. The resulting `NewRecord` is always `nil`.
+func (r *FormattingReconciler) Format() (*ReconcileResult, error) {
+	var texts []parsing.Text
+	for i, block := range parsing.GroupIntoBlocks(r.pr.lines) {
+		if i > 0 {
+			texts = append(texts, blankLine)
+		}
+		for _, l := range block {
+			level := r.pr.indentationLevel(l)
+			if level < 0 {
+				level = 0
+			}
+			texts = append(texts, parsing.Text{strings.TrimRightFunc(l.Text, unicode.IsSpace), level})
+		}
+	}
+	lines := parsing.Insert(nil, 0, texts, r.pr.preferences)
+	newText, _, err := validate(lines)
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileResult{
+		nil,
+		newText,
+	}, nil
+}
+
 func makeResult(ls []parsing.Line, recordIndex uint) (*ReconcileResult, error) {
-	newText := parsing.Join(ls)
-	newRecords, pErr := Parse(newText)
-	if pErr != nil {
-		err := pErr.Get()[0]
-		return nil, errors.New(err.Message())
+	newText, newRecords, err := validate(ls)
+	if err != nil {
+		return nil, err
 	}
 	return &ReconcileResult{
 		newRecords.Records[recordIndex],
 		newText,
 	}, nil
 }
+
+func validate(ls []parsing.Line) (string, *ParseResult, error) {
+	newText := parsing.Join(ls)
+	newRecords, pErr := Parse(newText)
+	if pErr != nil {
+		err := pErr.Get()[0]
+		return "", nil, errors.New(err.Message())
+	}
+	return newText, newRecords, nil
+}
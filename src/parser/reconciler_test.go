@@ -5,6 +5,7 @@ import (
 	"github.com/jotaen/klog/src/parser/parsing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
 )
 
@@ -63,6 +64,16 @@ func TestReconcilerAddsNewlyCreatedEntryAtEndOfFile(t *testing.T) {
 `, result.NewText)
 }
 
+func TestReconcilerKeepsRecordProperties(t *testing.T) {
+	original := "2018-01-01 (8h!, @office, billable)\n    1h\n"
+	pr, _ := Parse(original)
+	reconciler := NewRecordReconciler(pr, func(r Record) bool { return true })
+	result, err := reconciler.AppendEntry(func(r Record) string { return "2h" })
+	require.Nil(t, err)
+	assert.Equal(t, "2018-01-01 (8h!, @office, billable)\n    1h\n    2h\n", result.NewText)
+	assert.Equal(t, []Property{{"location", "office"}, {"billable", ""}}, result.NewRecord.Properties())
+}
+
 func TestReconcilerSkipsIfNoRecordMatches(t *testing.T) {
 	original := "2018-01-01\n"
 	pr, _ := Parse(original)
@@ -227,3 +238,329 @@ This and that
 2018-01-03
     3h`, result.NewText)
 }
+
+func TestReconcilerUpdatesEntryValue(t *testing.T) {
+	original := `
+2018-01-01
+Summary
+    1h Foo
+    15:00 -  16:00   Bar #baz
+    2h
+`
+	pr, _ := Parse(original)
+	reconciler := NewRecordReconciler(pr, func(r Record) bool { return true })
+	require.NotNil(t, reconciler)
+	result, err := reconciler.UpdateEntry(
+		func(i int, e Entry) bool { return i == 1 },
+		func(value string, s Summary) (string, Summary) {
+			assert.Equal(t, "15:00 -  16:00", value)
+			assert.Equal(t, Summary("Bar #baz"), s)
+			return "14:30 - 16:00", s
+		},
+	)
+	require.Nil(t, err)
+	assert.Equal(t, 90, result.NewRecord.Entries()[1].Duration().InMinutes())
+	assert.Equal(t, `
+2018-01-01
+Summary
+    1h Foo
+    14:30 - 16:00 Bar #baz
+    2h
+`, result.NewText)
+}
+
+func TestReconcilerUpdatesEntrySummaryAndKeepsValue(t *testing.T) {
+	original := "2018-01-01\n\t8:00-?\n\t1h Foo\r\n"
+	pr, _ := Parse(original)
+	reconciler := NewRecordReconciler(pr, func(r Record) bool { return true })
+	result, err := reconciler.UpdateEntry(
+		func(i int, e Entry) bool { return i == 0 },
+		func(value string, s Summary) (string, Summary) {
+			return value, "New summary"
+		},
+	)
+	require.Nil(t, err)
+	assert.Equal(t, "2018-01-01\n\t8:00-? New summary\n\t1h Foo\r\n", result.NewText)
+}
+
+func TestReconcilerRejectsInvalidEntryUpdate(t *testing.T) {
+	pr, _ := Parse("2018-01-01\n    1h\n")
+	reconciler := NewRecordReconciler(pr, func(r Record) bool { return true })
+	result, err := reconciler.UpdateEntry(
+		func(i int, e Entry) bool { return true },
+		func(value string, s Summary) (string, Summary) { return "asdf", s },
+	)
+	require.Nil(t, result)
+	assert.Error(t, err)
+}
+
+func TestReconcilerRemovesEntry(t *testing.T) {
+	original := `
+2018-01-01
+    1h Foo
+
+2018-01-02
+    2h Bar
+    3h Baz
+`
+	pr, _ := Parse(original)
+	reconciler := NewRecordReconciler(pr, func(r Record) bool {
+		return r.Date().ToString() == "2018-01-02"
+	})
+	result, err := reconciler.RemoveEntry(func(i int, e Entry) bool {
+		return strings.Contains(e.Summary().ToString(), "Bar")
+	})
+	require.Nil(t, err)
+	require.Len(t, result.NewRecord.Entries(), 1)
+	assert.Equal(t, `
+2018-01-01
+    1h Foo
+
+2018-01-02
+    3h Baz
+`, result.NewText)
+}
+
+func TestReconcilerPreservesComments(t *testing.T) {
+	original := `
+2018-01-01
+// Invoice sent
+    1h Foo
+    // Needs review
+    2h Bar
+    3h Baz
+`
+	pr, _ := Parse(original)
+	reconciler := NewRecordReconciler(pr, func(r Record) bool { return true })
+	result, err := reconciler.UpdateEntry(func(i int, e Entry) bool { return i == 2 }, func(value string, s Summary) (string, Summary) {
+		return "4h", s
+	})
+	require.Nil(t, err)
+	assert.Equal(t, `
+2018-01-01
+// Invoice sent
+    1h Foo
+    // Needs review
+    2h Bar
+    4h Baz
+`, result.NewText)
+
+	pr, _ = Parse(result.NewText)
+	reconciler = NewRecordReconciler(pr, func(r Record) bool { return true })
+	result, err = reconciler.RemoveEntry(func(i int, e Entry) bool { return i == 1 })
+	require.Nil(t, err)
+	assert.Equal(t, `
+2018-01-01
+// Invoice sent
+    1h Foo
+    // Needs review
+    4h Baz
+`, result.NewText)
+	assert.Equal(t, []string{"Invoice sent", "Needs review"}, result.NewRecord.Comments())
+}
+
+func TestReconcilerHandlesContinuationLines(t *testing.T) {
+	original := `
+2018-01-01
+  1h Foo
+    continued
+  2h Bar
+    still Bar
+  3h Baz
+`
+	pr, _ := Parse(original)
+	reconciler := NewRecordReconciler(pr, func(r Record) bool { return true })
+	result, err := reconciler.UpdateEntry(func(i int, e Entry) bool { return i == 1 }, func(value string, s Summary) (string, Summary) {
+		assert.Equal(t, "2h", value)
+		return "2h30m", "Bar\nnow with\nthree lines"
+	})
+	require.Nil(t, err)
+	assert.Equal(t, `
+2018-01-01
+  1h Foo
+    continued
+  2h30m Bar
+    now with
+    three lines
+  3h Baz
+`, result.NewText)
+
+	pr, _ = Parse(result.NewText)
+	reconciler = NewRecordReconciler(pr, func(r Record) bool { return true })
+	result, err = reconciler.RemoveEntry(func(i int, e Entry) bool { return i == 0 })
+	require.Nil(t, err)
+	assert.Equal(t, `
+2018-01-01
+  2h30m Bar
+    now with
+    three lines
+  3h Baz
+`, result.NewText)
+
+	pr, _ = Parse(result.NewText)
+	reconciler = NewRecordReconciler(pr, func(r Record) bool { return true })
+	result, err = reconciler.AppendEntry(func(r Record) string { return "4h Qux\nand more" })
+	require.Nil(t, err)
+	assert.Equal(t, `
+2018-01-01
+  2h30m Bar
+    now with
+    three lines
+  3h Baz
+  4h Qux
+    and more
+`, result.NewText)
+	assert.Equal(t, Summary("Qux\nand more"), result.NewRecord.Entries()[2].Summary())
+}
+
+func TestReconcilerKeepsCommentsBetweenContinuationLines(t *testing.T) {
+	original := `
+2018-01-01
+  1h Foo
+  // Comment
+    continued
+  2h Bar
+`
+	pr, _ := Parse(original)
+	result, err := NewRecordReconciler(pr, func(r Record) bool { return true }).
+		UpdateEntry(func(i int, e Entry) bool { return i == 0 }, func(value string, s Summary) (string, Summary) {
+			return "1h30m", "Foo\nchanged"
+		})
+	require.Nil(t, err)
+	assert.Equal(t, `
+2018-01-01
+  1h30m Foo
+    changed
+  // Comment
+  2h Bar
+`, result.NewText)
+
+	pr, _ = Parse(original)
+	result, err = NewRecordReconciler(pr, func(r Record) bool { return true }).
+		RemoveEntry(func(i int, e Entry) bool { return i == 0 })
+	require.Nil(t, err)
+	assert.Equal(t, `
+2018-01-01
+  // Comment
+  2h Bar
+`, result.NewText)
+}
+
+func TestReconcilerClosesOpenRangeWithContinuationLines(t *testing.T) {
+	original := `
+2018-01-01
+    15:00 - ? Started
+        working on
+`
+	pr, _ := Parse(original)
+	reconciler := NewRecordReconciler(pr, func(r Record) bool { return true })
+	result, err := reconciler.CloseOpenRange(func(r Record) (Time, Summary) {
+		return Ɀ_Time_(16, 0), "something"
+	})
+	require.Nil(t, err)
+	assert.Equal(t, `
+2018-01-01
+    15:00 - 16:00 Started
+        working on something
+`, result.NewText)
+}
+
+func TestReconcilerFailsIfNoEntryMatches(t *testing.T) {
+	pr, _ := Parse("2018-01-01\n    1h\n")
+	reconciler := NewRecordReconciler(pr, func(r Record) bool { return true })
+	result, err := reconciler.RemoveEntry(func(i int, e Entry) bool { return i == 1 })
+	require.Nil(t, result)
+	assert.Error(t, err)
+}
+
+func TestReconcilerRemovesRecords(t *testing.T) {
+	original := `
+2018-01-01
+    1h
+
+2018-01-02
+Foo
+    2h
+
+
+2018-01-03
+    3h
+
+2018-01-04
+`
+	pr, _ := Parse(original)
+	reconciler := NewRemovalReconciler(pr, func(r Record) bool {
+		return r.Date().Day() == 2 || r.Date().Day() == 4
+	})
+	require.NotNil(t, reconciler)
+	require.Len(t, reconciler.Records(), 2)
+	result, err := reconciler.RemoveRecords()
+	require.Nil(t, err)
+	assert.Nil(t, result.NewRecord)
+	assert.Equal(t, `
+2018-01-01
+    1h
+
+2018-01-03
+    3h
+`, result.NewText)
+}
+
+func TestReconcilerRemovesAllRecords(t *testing.T) {
+	original := "2018-01-01\n    1h\n\n2018-01-02\n    2h"
+	pr, _ := Parse(original)
+	reconciler := NewRemovalReconciler(pr, func(r Record) bool { return true })
+	result, err := reconciler.RemoveRecords()
+	require.Nil(t, err)
+	assert.Equal(t, "", result.NewText)
+}
+
+func TestReconcilerSkipsRemovalIfNoRecordMatches(t *testing.T) {
+	pr, _ := Parse("2018-01-01\n")
+	reconciler := NewRemovalReconciler(pr, func(r Record) bool { return false })
+	require.Nil(t, reconciler)
+}
+
+func TestReconcilerReturnsBlocksOfRecordsToRemove(t *testing.T) {
+	original := `
+2018-01-01
+    1h
+
+2018-01-02 (8h!)
+Foo
+	2h Bar
+`
+	pr, _ := Parse(original)
+	reconciler := NewRemovalReconciler(pr, func(r Record) bool {
+		return r.Date().Day() == 2
+	})
+	assert.Equal(t, [][]parsing.Text{{
+		{"2018-01-02 (8h!)", 0},
+		{"Foo", 0},
+		{"2h Bar", 1},
+	}}, reconciler.Blocks())
+}
+
+func TestReconcilerReturnsBlocksWithContinuationLines(t *testing.T) {
+	original := `
+2018-01-02
+  2h Bar
+    continued
+`
+	pr, _ := Parse(original)
+	reconciler := NewRemovalReconciler(pr, func(r Record) bool { return true })
+	assert.Equal(t, [][]parsing.Text{{
+		{"2018-01-02", 0},
+		{"2h Bar", 1},
+		{"continued", 2},
+	}}, reconciler.Blocks())
+}
+
+func TestReconcilerFormatsFile(t *testing.T) {
+	original := "\n\n// Comment\n\n\n2018-01-01 \n  // Inside   \n\t1h Foo  \n\t\t  continued\n\n\n\n2018-01-02\n  2h\n"
+	pr, _ := Parse(original)
+	result, err := NewFormattingReconciler(pr).Format()
+	require.Nil(t, err)
+	assert.Nil(t, result.NewRecord)
+	assert.Equal(t, "// Comment\n\n2018-01-01\n  // Inside\n  1h Foo\n    continued\n\n2018-01-02\n  2h\n", result.NewText)
+}
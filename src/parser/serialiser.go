@@ -1,24 +1,63 @@
 package parser
 
 import (
+	"errors"
 	. "github.com/jotaen/klog/src"
+	"github.com/jotaen/klog/src/parser/parsing"
 	"strings"
 )
 
 // SerialiseRecords serialises records into the canonical string representation.
 func (h *Serialiser) SerialiseRecords(rs ...Record) string {
+	return h.serialiseRecords(rs, false)
+}
+
+// SerialiseRecordsWithComments is like `SerialiseRecords`, but it also includes
+// the comments of the records. These are placed above the respective record.
+func (h *Serialiser) SerialiseRecordsWithComments(rs ...Record) string {
+	return h.serialiseRecords(rs, true)
+}
+
+// SerialiseRecordToTexts serialises a record (including its comments) into
+// lines of text, so that it can be inserted via the `BlockReconciler`.
+func SerialiseRecordToTexts(r Record) ([]parsing.Text, error) {
+	text := PlainSerialiser.SerialiseRecordsWithComments(r)
+	pr, errs := Parse(text)
+	if errs != nil {
+		return nil, errors.New(errs.Get()[0].Message())
+	}
+	var texts []parsing.Text
+	for _, l := range pr.lines {
+		texts = append(texts, parsing.Text{l.Text, pr.indentationLevel(l)})
+	}
+	return texts, nil
+}
+
+func (h *Serialiser) serialiseRecords(rs []Record, withComments bool) string {
 	var text []string
 	for _, r := range rs {
-		text = append(text, h.serialiseRecord(r))
+		text = append(text, h.serialiseRecord(r, withComments))
 	}
 	return strings.Join(text, "\n")
 }
 
-func (h *Serialiser) serialiseRecord(r Record) string {
+func (h *Serialiser) serialiseRecord(r Record, withComments bool) string {
 	text := ""
+	if withComments {
+		for _, c := range r.Comments() {
+			text += h.Comment(c) + "\n"
+		}
+	}
 	text += h.Date(r.Date())
+	var props []string
 	if r.ShouldTotal().InMinutes() != 0 {
-		text += " (" + h.ShouldTotal(r.ShouldTotal()) + ")"
+		props = append(props, h.ShouldTotal(r.ShouldTotal()))
+	}
+	for _, p := range r.Properties() {
+		props = append(props, h.Property(p))
+	}
+	if len(props) > 0 {
+		text += " (" + strings.Join(props, ", ") + ")"
 	}
 	text += "\n"
 	if r.Summary() != "" {
@@ -31,8 +70,14 @@ func (h *Serialiser) serialiseRecord(r Record) string {
 			func(d Duration) interface{} { return h.Duration(d) },
 			func(o OpenRange) interface{} { return h.OpenRange(o) },
 		)).(string)
-		if e.Summary() != "" {
-			text += " " + h.Summary(e.Summary())
+		for i, l := range strings.Split(e.Summary().ToString(), "\n") {
+			if i == 0 {
+				if l != "" {
+					text += " " + h.Summary(Summary(l))
+				}
+				continue
+			}
+			text += "\n        " + h.Summary(Summary(l)) // continuation line
 		}
 		text += "\n"
 	}
@@ -42,21 +87,29 @@ func (h *Serialiser) serialiseRecord(r Record) string {
 type Serialiser struct {
 	Date           func(Date) string
 	ShouldTotal    func(Duration) string
+	Property       func(Property) string
 	Summary        func(Summary) string
 	Range          func(Range) string
 	OpenRange      func(OpenRange) string
 	Duration       func(Duration) string
 	SignedDuration func(Duration) string
 	Time           func(Time) string
+	Comment        func(string) string
 }
 
 var PlainSerialiser = Serialiser{
 	Date:           Date.ToString,
 	ShouldTotal:    Duration.ToString,
+	Property:       Property.ToString,
 	Summary:        Summary.ToString,
 	Range:          Range.ToString,
 	OpenRange:      OpenRange.ToString,
 	Duration:       Duration.ToString,
 	SignedDuration: Duration.ToStringWithSign,
 	Time:           Time.ToString,
+	Comment:        plainComment,
+}
+
+func plainComment(text string) string {
+	return parsing.COMMENT_PREFIX + " " + text
 }
@@ -56,3 +56,42 @@ func TestSerialiseMultipleRecords(t *testing.T) {
 2020-01-20
 `, text)
 }
+
+func TestSerialiseRecordsWithComments(t *testing.T) {
+	r := klog.NewRecord(klog.Ɀ_Date_(2020, 01, 15))
+	r.AddComment("Invoice sent")
+	r.AddDuration(klog.NewDuration(1, 0), "")
+	assert.Equal(t, `2020-01-15
+    1h
+`, PlainSerialiser.SerialiseRecords(r))
+	assert.Equal(t, `// Invoice sent
+2020-01-15
+    1h
+`, PlainSerialiser.SerialiseRecordsWithComments(r))
+}
+
+func TestSerialiseEntrySummaryWithContinuationLines(t *testing.T) {
+	r := klog.NewRecord(klog.Ɀ_Date_(2020, 01, 15))
+	r.AddDuration(klog.NewDuration(1, 0), "Foo\nBar")
+	r.AddDuration(klog.NewDuration(2, 0), "\nBaz")
+	text := PlainSerialiser.SerialiseRecords(r)
+	assert.Equal(t, `2020-01-15
+    1h Foo
+        Bar
+    2h
+        Baz
+`, text)
+	pr, _ := Parse(text)
+	assert.Equal(t, r.Entries()[0].Summary(), pr.Records[0].Entries()[0].Summary())
+	assert.Equal(t, r.Entries()[1].Summary(), pr.Records[0].Entries()[1].Summary())
+}
+
+func TestSerialiseRecordProperties(t *testing.T) {
+	text := "2020-01-15 (8h!, @office, billable, tz=Europe/Berlin)\n"
+	pr, _ := Parse(text)
+	assert.Equal(t, text, PlainSerialiser.SerialiseRecords(pr.Records...))
+
+	r := klog.NewRecord(klog.Ɀ_Date_(2020, 01, 15))
+	r.SetProperty(klog.Property{Key: "billable"})
+	assert.Equal(t, "2020-01-15 (billable)\n", PlainSerialiser.SerialiseRecords(r))
+}
@@ -1,37 +1,130 @@
 package parser
 
 import (
-	"errors"
 	"github.com/jotaen/klog/src"
 	"github.com/jotaen/klog/src/parser/parsing"
 	"regexp"
+	"strconv"
 	"strings"
 	gotime "time"
 )
 
-var markerPattern = regexp.MustCompile(`{{.+}}`)
-
-func RenderTemplate(templateText string, time gotime.Time) ([]parsing.Text, error) {
-	today := klog.NewDateFromTime(time)
-	now := klog.NewTimeFromTime(time)
-	variables := map[string]string{
-		"TODAY":     today.ToString(),
-		"YESTERDAY": today.PlusDays(-1).ToString(),
-		"NOW":       now.ToString(),
-	}
-	instance := markerPattern.ReplaceAllStringFunc(templateText, func(m string) string {
-		m = strings.TrimLeft(m, "{{")
-		m = strings.TrimRight(m, "}}")
-		m = strings.TrimSpace(m)
-		return variables[m]
-	})
+var markerPattern = regexp.MustCompile(`{{(.*?)}}`)
+
+// TEMPLATE_BUILTINS are the names of the variables that are always available
+// in templates. They are upper-case, to distinguish them from parameters.
+var TEMPLATE_BUILTINS = []string{"TODAY", "YESTERDAY", "NOW", "WEEKDAY", "WEEK", "SHOULD"}
+
+// TemplateBuiltins returns the values of the built-in variables. All date
+// related values refer to the date of the record (which might not be today),
+// whereas `NOW` always is the current time. `SHOULD` is empty if there is no
+// should-total (e.g. because no schedule is configured).
+func TemplateBuiltins(date klog.Date, now gotime.Time, shouldTotal klog.ShouldTotal) map[string]string {
+	should := ""
+	if shouldTotal != nil {
+		should = shouldTotal.ToString()
+	}
+	return map[string]string{
+		"TODAY":     date.ToString(),
+		"YESTERDAY": date.PlusDays(-1).ToString(),
+		"NOW":       klog.NewTimeFromTime(now).ToString(),
+		"WEEKDAY":   gotime.Weekday(date.Weekday() % 7).String(),
+		"WEEK":      strconv.Itoa(date.WeekNumber()),
+		"SHOULD":    should,
+	}
+}
+
+// TemplateParameter is a variable of a template that is not built-in.
+// Its default value can be declared in any of its markers: `{{ name = value }}`.
+type TemplateParameter struct {
+	Name    string
+	Default *string
+}
+
+// TemplateParameters returns the parameters of a template, in the order
+// of their first occurrence.
+func TemplateParameters(templateText string) []TemplateParameter {
+	var result []TemplateParameter
+	index := make(map[string]int)
+	for _, m := range markerPattern.FindAllStringSubmatch(templateText, -1) {
+		name, defaultValue := parseMarker(m[1])
+		if name == "" || isBuiltin(name) {
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(result)
+			index[name] = i
+			result = append(result, TemplateParameter{Name: name})
+		}
+		if defaultValue != nil && result[i].Default == nil {
+			result[i].Default = defaultValue
+		}
+	}
+	return result
+}
+
+// RenderTemplate replaces all markers in the template with the values of the
+// respective variables, or with the declared defaults. The errors refer
+// to the respective lines of the template.
+func RenderTemplate(templateText string, variables map[string]string) ([]parsing.Text, error) {
+	defaults := make(map[string]string)
+	for _, p := range TemplateParameters(templateText) {
+		if p.Default != nil {
+			defaults[p.Name] = *p.Default
+		}
+	}
+	instance := ""
+	var errs []parsing.Error
+	for _, l := range parsing.Split(templateText) {
+		text := ""
+		position := 0
+		for _, m := range markerPattern.FindAllStringSubmatchIndex(l.Text, -1) {
+			text += l.Text[position:m[0]]
+			position = m[1]
+			name, _ := parseMarker(l.Text[m[2]:m[3]])
+			if value, ok := variables[name]; ok {
+				text += value
+			} else if value, ok := defaults[name]; ok {
+				text += value
+			} else {
+				errs = append(errs, ErrorMissingTemplateValue(parsing.NewError(l, m[0], m[1]-m[0])))
+			}
+		}
+		l.Text = text + l.Text[position:]
+		instance += l.Original()
+	}
+	if len(errs) > 0 {
+		return nil, parsing.NewErrors(errs)
+	}
 	pr, err := Parse(instance)
 	if err != nil {
-		return nil, errors.New("Cannot parse:\n" + instance)
+		return nil, err
 	}
 	var texts []parsing.Text
 	for _, l := range pr.lines {
-		texts = append(texts, parsing.Text{l.Text, l.IndentationLevel()})
+		texts = append(texts, parsing.Text{l.Text, pr.indentationLevel(l)})
 	}
 	return texts, nil
 }
+
+// parseMarker splits the contents of a marker into the variable name and the
+// default value, if there is one.
+func parseMarker(m string) (string, *string) {
+	parts := strings.SplitN(m, "=", 2)
+	name := strings.TrimSpace(parts[0])
+	if len(parts) == 1 {
+		return name, nil
+	}
+	defaultValue := strings.TrimSpace(parts[1])
+	return name, &defaultValue
+}
+
+func isBuiltin(name string) bool {
+	for _, b := range TEMPLATE_BUILTINS {
+		if b == name {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"github.com/jotaen/klog/src"
 	"github.com/jotaen/klog/src/parser/parsing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -18,7 +19,7 @@ Foo #xyz
 {{YESTERDAY}} (8h30m!)
 	1h
 	{{ NOW }} - ?
-`, now)
+`, TemplateBuiltins(klog.NewDateFromTime(now), now, klog.NewShouldTotal(0, 0)))
 	require.Nil(t, err)
 	assert.Equal(t, []parsing.Text{
 		{"", 0},
@@ -31,11 +32,76 @@ Foo #xyz
 	}, result)
 }
 
+func TestRenderTemplateWithAdditionalBuiltins(t *testing.T) {
+	result, err := RenderTemplate(`{{ TODAY }} ({{ SHOULD }})
+{{ WEEKDAY }} in week {{ WEEK }}
+`, TemplateBuiltins(klog.NewDateFromTime(now), now, klog.NewShouldTotal(7, 30)))
+	require.Nil(t, err)
+	assert.Equal(t, []parsing.Text{
+		{"1995-03-31 (7h30m!)", 0},
+		{"Friday in week 13", 0},
+	}, result)
+}
+
+func TestRenderTemplateWithoutShouldTotal(t *testing.T) {
+	result, err := RenderTemplate(`{{ TODAY }}
+Should: [{{ SHOULD }}]
+`, TemplateBuiltins(klog.NewDateFromTime(now), now, nil))
+	require.Nil(t, err)
+	assert.Equal(t, []parsing.Text{
+		{"1995-03-31", 0},
+		{"Should: []", 0},
+	}, result)
+}
+
+func TestRenderTemplateWithParameters(t *testing.T) {
+	variables := TemplateBuiltins(klog.NewDateFromTime(now), now, klog.NewShouldTotal(0, 0))
+	variables["project"] = "klog"
+	variables["end"] = "?"
+	result, err := RenderTemplate(`{{ TODAY }}
+Working on #{{ project = foo }}
+    {{ start = 8:00 }} - {{ end }} #{{ project }}
+`, variables)
+	require.Nil(t, err, err)
+	require.Len(t, result, 3)
+	assert.Equal(t, "Working on #klog", result[1].Text)
+	assert.Equal(t, parsing.Text{"8:00 - ? #klog", 1}, result[2])
+}
+
+func TestReportsMissingTemplateValue(t *testing.T) {
+	result, err := RenderTemplate(`{{ TODAY }}
+    {{ start = 8:00 }} - {{ end }}
+`, TemplateBuiltins(klog.NewDateFromTime(now), now, klog.NewShouldTotal(0, 0)))
+	require.Nil(t, result)
+	require.IsType(t, parsing.NewErrors(nil), err)
+	errs := err.(parsing.Errors).Get()
+	require.Len(t, errs, 1)
+	assert.Equal(t, "ErrorMissingTemplateValue", errs[0].Code())
+	assert.Equal(t, 2, errs[0].Context().LineNumber)
+	assert.Equal(t, 21, errs[0].Position())
+	assert.Equal(t, 9, errs[0].Length())
+}
+
 func TestTemplateFailsIfNoValidRecord(t *testing.T) {
 	result, err := RenderTemplate(`
 {{ TODAY }} foo
 	This is all malformed
-`, now)
+`, TemplateBuiltins(klog.NewDateFromTime(now), now, klog.NewShouldTotal(0, 0)))
 	require.Error(t, err)
 	require.Nil(t, result)
+	errs := err.(parsing.Errors).Get()
+	assert.Equal(t, 2, errs[0].Context().LineNumber)
+}
+
+func TestTemplateParameters(t *testing.T) {
+	params := TemplateParameters(`{{ TODAY }} ({{ should = 8h! }})
+    {{ start }} - {{ end = ? }} {{ start = 9:00 }} {{ WEEK }}
+`)
+	require.Len(t, params, 3)
+	assert.Equal(t, "should", params[0].Name)
+	assert.Equal(t, "8h!", *params[0].Default)
+	assert.Equal(t, "start", params[1].Name)
+	assert.Equal(t, "9:00", *params[1].Default)
+	assert.Equal(t, "end", params[2].Name)
+	assert.Equal(t, "?", *params[2].Default)
 }
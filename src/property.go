@@ -0,0 +1,83 @@
+package klog
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Property is an additional piece of information about a Record, which is
+// specified in the headline. Properties without value (e.g. `billable`)
+// are flags.
+type Property struct {
+	Key   string
+	Value string
+}
+
+// LOCATION_PROPERTY is the key of the location, which can also be written
+// in short form, e.g. `@office`.
+const LOCATION_PROPERTY = "location"
+
+// BILLABLE_PROPERTY is the key of the flag that marks a record as billable.
+const BILLABLE_PROPERTY = "billable"
+
+// flagKeys are the keys of all known flags. Other keys require a value, so
+// that typos (e.g. a should-total without `!`) are not taken as flags.
+var flagKeys = map[string]bool{
+	BILLABLE_PROPERTY: true,
+}
+
+// Keys must start with a letter, so that they can’t be confused with durations.
+var propertyKeyPattern = regexp.MustCompile(`^\p{L}[\p{L}\d_-]*$`)
+var propertyValuePattern = regexp.MustCompile(`^[^\s,()!]+$`)
+
+func NewProperty(key string, value string) (Property, error) {
+	if !IsValidPropertyKey(key) {
+		return Property{}, errors.New("MALFORMED_PROPERTY_KEY")
+	}
+	key = strings.ToLower(key)
+	if value == "" && !flagKeys[key] {
+		return Property{}, errors.New("UNKNOWN_FLAG")
+	}
+	if value != "" && !propertyValuePattern.MatchString(value) {
+		return Property{}, errors.New("MALFORMED_PROPERTY_VALUE")
+	}
+	return Property{key, value}, nil
+}
+
+// IsValidPropertyKey checks whether a text is syntactically valid as key.
+func IsValidPropertyKey(key string) bool {
+	return propertyKeyPattern.MatchString(key)
+}
+
+// NewPropertyFromString parses a property in one of the forms `key`,
+// `key=value` or `@location`.
+func NewPropertyFromString(text string) (Property, error) {
+	key, value := text, ""
+	if strings.HasPrefix(text, "@") {
+		key, value = LOCATION_PROPERTY, text[1:]
+	} else if parts := strings.SplitN(text, "=", 2); len(parts) == 2 {
+		key, value = parts[0], parts[1]
+	} else {
+		return NewProperty(key, "")
+	}
+	if value == "" {
+		return Property{}, errors.New("MALFORMED_PROPERTY_VALUE")
+	}
+	return NewProperty(key, value)
+}
+
+// IsFlag checks whether the property doesn’t have a value.
+func (p Property) IsFlag() bool {
+	return p.Value == ""
+}
+
+func (p Property) ToString() string {
+	if p.Key == LOCATION_PROPERTY && p.Value != "" {
+		return "@" + p.Value
+	}
+	if p.IsFlag() {
+		return p.Key
+	}
+	return p.Key + "=" + p.Value
+}
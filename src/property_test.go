@@ -0,0 +1,48 @@
+package klog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParsePropertiesFromString(t *testing.T) {
+	for _, x := range []struct {
+		text   string
+		expect Property
+	}{
+		{"billable", Property{"billable", ""}},
+		{"Billable", Property{"billable", ""}},
+		{"@office", Property{"location", "office"}},
+		{"tz=Europe/Berlin", Property{"tz", "Europe/Berlin"}},
+		{"project_id=A-123", Property{"project_id", "A-123"}},
+	} {
+		p, err := NewPropertyFromString(x.text)
+		require.Nil(t, err, x.text)
+		assert.Equal(t, x.expect, p, x.text)
+	}
+}
+
+func TestParsingPropertyFailsIfMalformed(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"@",
+		"tz=",
+		"=Europe/Berlin",
+		"a/b",
+		"a=b)",
+		"a=b c",
+		"8h",
+		"8h=x",
+		"asdf",
+	} {
+		_, err := NewPropertyFromString(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestSerialiseProperty(t *testing.T) {
+	assert.Equal(t, "billable", Property{"billable", ""}.ToString())
+	assert.Equal(t, "@office", Property{"location", "office"}.ToString())
+	assert.Equal(t, "tz=Europe/Berlin", Property{"tz", "Europe/Berlin"}.ToString())
+}
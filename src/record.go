@@ -7,7 +7,7 @@ import (
 
 // SPEC_VERSION contains the version number of the file format
 // specification which this implementation is based on.
-const SPEC_VERSION = "1.0"
+const SPEC_VERSION = "1.1"
 
 // Record is a standalone piece of data that holds the time tracking
 // information associated with a certain date.
@@ -16,10 +16,17 @@ type Record interface {
 
 	ShouldTotal() ShouldTotal
 	SetShouldTotal(Duration)
+	HasShouldTotal() bool
 
 	Summary() Summary
 	SetSummary(string) error
 
+	// Properties are the additional key/value pairs in the headline, in
+	// the order in which they appear.
+	Properties() []Property
+	Property(string) (Property, bool)
+	SetProperty(Property)
+
 	Entries() []Entry
 	SetEntries([]Entry)
 	AddDuration(Duration, Summary)
@@ -27,6 +34,10 @@ type Record interface {
 	OpenRange() OpenRange
 	StartOpenRange(Time, Summary) error
 	EndOpenRange(Time) error
+
+	// Comments are notes that don’t have any meaning for the evaluation.
+	Comments() []string
+	AddComment(string)
 }
 
 func NewRecord(date Date) Record {
@@ -53,7 +64,9 @@ type record struct {
 	date        Date
 	shouldTotal ShouldTotal
 	summary     Summary
+	properties  []Property
 	entries     []Entry
+	comments    []string
 }
 
 func (r *record) Date() Date {
@@ -71,6 +84,10 @@ func (r *record) SetShouldTotal(t Duration) {
 	r.shouldTotal = NewShouldTotal(0, t.InMinutes())
 }
 
+func (r *record) HasShouldTotal() bool {
+	return r.shouldTotal != nil
+}
+
 func (r *record) Summary() Summary {
 	return r.summary
 }
@@ -83,6 +100,30 @@ func (r *record) SetSummary(summary string) error {
 	return nil
 }
 
+func (r *record) Properties() []Property {
+	return r.properties
+}
+
+func (r *record) Property(key string) (Property, bool) {
+	for _, p := range r.properties {
+		if p.Key == key {
+			return p, true
+		}
+	}
+	return Property{}, false
+}
+
+// SetProperty adds a property, or replaces an existing one with the same key.
+func (r *record) SetProperty(p Property) {
+	for i, existing := range r.properties {
+		if existing.Key == p.Key {
+			r.properties[i] = p
+			return
+		}
+	}
+	r.properties = append(r.properties, p)
+}
+
 func (r *record) Entries() []Entry {
 	return r.entries
 }
@@ -131,3 +172,11 @@ func (r *record) EndOpenRange(end Time) error {
 	}
 	return errors.New("NO_OPEN_RANGE")
 }
+
+func (r *record) Comments() []string {
+	return r.comments
+}
+
+func (r *record) AddComment(text string) {
+	r.comments = append(r.comments, text)
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	. "github.com/jotaen/klog/src"
+)
+
+// BalancePeriod is the interval at which the carry-over rules of a
+// balance take effect.
+type BalancePeriod int
+
+const (
+	NoBalancePeriod BalancePeriod = iota
+	MonthlyBalancePeriod
+	QuarterlyBalancePeriod
+	YearlyBalancePeriod
+)
+
+// BalanceRules determine how the flex-time balance is accumulated.
+type BalanceRules struct {
+	// Initial is the balance before the first day.
+	Initial Duration
+
+	// Period is the interval at whose end the balance is capped (`MaxCarryOver`)
+	// or reset (`Reset`).
+	Period       BalancePeriod
+	MaxCarryOver Duration
+	Reset        bool
+
+	// Corrections are manual adjustments of the balance, e.g. for payouts.
+	Corrections []BalanceCorrection
+}
+
+type BalanceCorrection struct {
+	Date  Date
+	Value Duration
+	Note  string
+}
+
+// BalanceStep is the state of the balance at the end of a certain day.
+type BalanceStep struct {
+	Date Date
+
+	// Diff is the difference between total and should-total of the day.
+	Diff Duration
+
+	// Adjustment is the sum of all corrections of that day, plus the amount
+	// that was forfeited due to the carry-over rules.
+	Adjustment Duration
+
+	Balance Duration
+}
+
+// RunningBalance accumulates the differences between total and should-total
+// chronologically. Days without record count as owed time, if the schedule
+// requires it. The records don’t need to be sorted.
+func RunningBalance(rules *BalanceRules, schedule *Schedule, rs ...Record) []BalanceStep {
+	if rules == nil {
+		rules = &BalanceRules{}
+	}
+	sorted := Sort(rs, true)
+	var dates []Date
+	if len(sorted) > 0 {
+		dates = allDates(sorted[0].Date(), sorted[len(sorted)-1].Date())
+	}
+	for _, c := range rules.Corrections {
+		if len(dates) == 0 {
+			dates = []Date{c.Date}
+		}
+		if !c.Date.IsAfterOrEqual(dates[0]) {
+			dates = append(allDates(c.Date, dates[0].PlusDays(-1)), dates...)
+		} else if !dates[len(dates)-1].IsAfterOrEqual(c.Date) {
+			dates = append(dates, allDates(dates[len(dates)-1].PlusDays(1), c.Date)...)
+		}
+	}
+
+	recordsByDay := make(map[DayHash][]Record)
+	for _, r := range sorted {
+		h := NewDayHash(r.Date())
+		recordsByDay[h] = append(recordsByDay[h], r)
+	}
+	correctionsByDay := make(map[DayHash]Duration)
+	for _, c := range rules.Corrections {
+		h := NewDayHash(c.Date)
+		if correctionsByDay[h] == nil {
+			correctionsByDay[h] = NewDuration(0, 0)
+		}
+		correctionsByDay[h] = correctionsByDay[h].Plus(c.Value)
+	}
+
+	var steps []BalanceStep
+	balance := rules.Initial
+	if balance == nil {
+		balance = NewDuration(0, 0)
+	}
+	for i, d := range dates {
+		adjustment := NewDuration(0, 0)
+		if i > 0 && rules.isNewPeriod(dates[i-1], d) {
+			carryOver := rules.carryOver(balance)
+			adjustment = carryOver.Minus(balance)
+			balance = carryOver
+		}
+		dayRecords := recordsByDay[NewDayHash(d)]
+		should := ShouldTotalSum(schedule, dayRecords...)
+		if len(dayRecords) == 0 {
+			should = UnrecordedShouldTotal(schedule, d, d)
+		}
+		diff := Diff(should, Total(dayRecords...).Plus(CreditSum(schedule, dayRecords...)))
+		if correction := correctionsByDay[NewDayHash(d)]; correction != nil {
+			adjustment = adjustment.Plus(correction)
+			balance = balance.Plus(correction)
+		}
+		if len(dayRecords) == 0 && diff.InMinutes() == 0 && adjustment.InMinutes() == 0 {
+			continue
+		}
+		balance = balance.Plus(diff)
+		steps = append(steps, BalanceStep{
+			Date:       d,
+			Diff:       diff,
+			Adjustment: adjustment,
+			Balance:    balance,
+		})
+	}
+	return steps
+}
+
+func (rules *BalanceRules) isNewPeriod(previous Date, current Date) bool {
+	switch rules.Period {
+	case MonthlyBalancePeriod:
+		return NewMonthHash(previous) != NewMonthHash(current)
+	case QuarterlyBalancePeriod:
+		return NewQuarterHash(previous) != NewQuarterHash(current)
+	case YearlyBalancePeriod:
+		return NewYearHash(previous) != NewYearHash(current)
+	}
+	return false
+}
+
+func (rules *BalanceRules) carryOver(balance Duration) Duration {
+	if rules.Reset {
+		return NewDuration(0, 0)
+	}
+	if rules.MaxCarryOver != nil && balance.InMinutes() > rules.MaxCarryOver.InMinutes() {
+		return rules.MaxCarryOver
+	}
+	return balance
+}
+
+func allDates(from Date, until Date) []Date {
+	var result []Date
+	for d := from; until.IsAfterOrEqual(d); d = d.PlusDays(1) {
+		result = append(result, d)
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	. "github.com/jotaen/klog/src"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func recordWith(d Date, should Duration, total Duration) Record {
+	r := NewRecord(d)
+	if should != nil {
+		r.SetShouldTotal(should)
+	}
+	r.AddDuration(total, "")
+	return r
+}
+
+func TestRunningBalanceAccumulatesDiffs(t *testing.T) {
+	steps := RunningBalance(&BalanceRules{Initial: NewDuration(1, 0)}, nil,
+		recordWith(Ɀ_Date_(2020, 1, 3), NewDuration(8, 0), NewDuration(6, 0)),
+		recordWith(Ɀ_Date_(2020, 1, 1), NewDuration(8, 0), NewDuration(9, 30)),
+	)
+	assert.Len(t, steps, 2)
+	assert.Equal(t, Ɀ_Date_(2020, 1, 1), steps[0].Date)
+	assert.Equal(t, NewDuration(1, 30), steps[0].Diff)
+	assert.Equal(t, NewDuration(2, 30), steps[0].Balance)
+	assert.Equal(t, Ɀ_Date_(2020, 1, 3), steps[1].Date)
+	assert.Equal(t, NewDuration(0, 30), steps[1].Balance)
+}
+
+func TestRunningBalanceIncludesUnrecordedDaysFromSchedule(t *testing.T) {
+	s := &Schedule{Periods: []SchedulePeriod{{
+		Since: Ɀ_Date_(2020, 1, 1),
+		Hours: map[int]Duration{3: NewDuration(8, 0), 4: NewDuration(8, 0), 5: NewDuration(8, 0)},
+	}}}
+	steps := RunningBalance(nil, s,
+		recordWith(Ɀ_Date_(2020, 1, 1), nil, NewDuration(10, 0)),
+		recordWith(Ɀ_Date_(2020, 1, 3), nil, NewDuration(8, 0)),
+	)
+	assert.Len(t, steps, 3)
+	assert.Equal(t, NewDuration(-8, 0), steps[1].Diff)
+	assert.Equal(t, NewDuration(-6, 0), steps[2].Balance)
+}
+
+func TestRunningBalanceAppliesCapsAndCorrections(t *testing.T) {
+	steps := RunningBalance(&BalanceRules{
+		Period:       QuarterlyBalancePeriod,
+		MaxCarryOver: NewDuration(2, 0),
+		Corrections: []BalanceCorrection{
+			{Date: Ɀ_Date_(2020, 4, 2), Value: NewDuration(-1, 0), Note: "Payout"},
+		},
+	}, nil,
+		recordWith(Ɀ_Date_(2020, 3, 31), NewDuration(0, 0), NewDuration(5, 0)),
+		recordWith(Ɀ_Date_(2020, 4, 3), NewDuration(0, 0), NewDuration(1, 0)),
+	)
+	assert.Len(t, steps, 4)
+	assert.Equal(t, NewDuration(5, 0), steps[0].Balance)
+	assert.Equal(t, Ɀ_Date_(2020, 4, 1), steps[1].Date)
+	assert.Equal(t, NewDuration(-3, 0), steps[1].Adjustment)
+	assert.Equal(t, NewDuration(2, 0), steps[1].Balance)
+	assert.Equal(t, NewDuration(-1, 0), steps[2].Adjustment)
+	assert.Equal(t, NewDuration(2, 0), steps[3].Balance)
+}
+
+func TestRunningBalanceResetsAtPeriodEnd(t *testing.T) {
+	steps := RunningBalance(&BalanceRules{
+		Period: YearlyBalancePeriod,
+		Reset:  true,
+	}, nil,
+		recordWith(Ɀ_Date_(2020, 12, 31), NewDuration(8, 0), NewDuration(5, 0)),
+		recordWith(Ɀ_Date_(2021, 1, 1), NewDuration(8, 0), NewDuration(9, 0)),
+	)
+	assert.Len(t, steps, 2)
+	assert.Equal(t, NewDuration(3, 0), steps[1].Adjustment)
+	assert.Equal(t, NewDuration(1, 0), steps[1].Balance)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	. "github.com/jotaen/klog/src"
+	gosort "sort"
+	"strings"
+)
+
+type ChangeType int
+
+const (
+	RECORD_ADDED ChangeType = iota
+	RECORD_REMOVED
+	RECORD_CHANGED
+)
+
+// RecordChange describes how the records of a date differ between two
+// versions of the records.
+type RecordChange struct {
+	Date           Date
+	Type           ChangeType
+	AddedEntries   []Entry
+	RemovedEntries []Entry
+	// TotalDelta is the difference of the totals, i.e. new minus old.
+	TotalDelta Duration
+}
+
+// CompareRecords determines the changes between the old and the new records,
+// ordered by date. Records of the same date are treated as one. Entries are
+// compared by their value and summary, so a modified entry shows up as one
+// removed and one added entry.
+func CompareRecords(oldRecords []Record, newRecords []Record) []RecordChange {
+	oldByDate := groupByDate(oldRecords)
+	newByDate := groupByDate(newRecords)
+	var dates []Date
+	for _, rs := range oldByDate {
+		dates = append(dates, rs[0].Date())
+	}
+	for h, rs := range newByDate {
+		if _, ok := oldByDate[h]; !ok {
+			dates = append(dates, rs[0].Date())
+		}
+	}
+	gosort.Slice(dates, func(i, j int) bool {
+		return !dates[i].IsAfterOrEqual(dates[j])
+	})
+
+	var changes []RecordChange
+	for _, d := range dates {
+		olds := oldByDate[NewDayHash(d)]
+		news := newByDate[NewDayHash(d)]
+		change := RecordChange{
+			Date:       d,
+			Type:       RECORD_CHANGED,
+			TotalDelta: Total(news...).Minus(Total(olds...)),
+		}
+		if len(olds) == 0 {
+			change.Type = RECORD_ADDED
+		} else if len(news) == 0 {
+			change.Type = RECORD_REMOVED
+		}
+		change.RemovedEntries, change.AddedEntries = compareEntries(entriesOf(olds), entriesOf(news))
+		if change.Type == RECORD_CHANGED && len(change.AddedEntries) == 0 && len(change.RemovedEntries) == 0 &&
+			headlineOf(olds) == headlineOf(news) {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func groupByDate(rs []Record) map[DayHash][]Record {
+	result := make(map[DayHash][]Record)
+	for _, r := range rs {
+		h := NewDayHash(r.Date())
+		result[h] = append(result[h], r)
+	}
+	return result
+}
+
+func entriesOf(rs []Record) []Entry {
+	var result []Entry
+	for _, r := range rs {
+		result = append(result, r.Entries()...)
+	}
+	return result
+}
+
+// headlineOf summarises everything about the records except for the entries.
+func headlineOf(rs []Record) string {
+	var parts []string
+	for _, r := range rs {
+		if r.HasShouldTotal() {
+			parts = append(parts, r.ShouldTotal().ToString())
+		}
+		if r.Summary() != "" {
+			parts = append(parts, r.Summary().ToString())
+		}
+		for _, p := range r.Properties() {
+			parts = append(parts, p.ToString())
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// compareEntries returns the entries that only appear in the old entries,
+// and the ones that only appear in the new entries.
+func compareEntries(oldEntries []Entry, newEntries []Entry) ([]Entry, []Entry) {
+	remaining := make(map[string]int)
+	for _, e := range newEntries {
+		remaining[entryKey(e)]++
+	}
+	var removed []Entry
+	for _, e := range oldEntries {
+		k := entryKey(e)
+		if remaining[k] > 0 {
+			remaining[k]--
+			continue
+		}
+		removed = append(removed, e)
+	}
+	unmatched := make(map[string]int)
+	for _, e := range oldEntries {
+		unmatched[entryKey(e)]++
+	}
+	var added []Entry
+	for _, e := range newEntries {
+		k := entryKey(e)
+		if unmatched[k] > 0 {
+			unmatched[k]--
+			continue
+		}
+		added = append(added, e)
+	}
+	return removed, added
+}
+
+func entryKey(e Entry) string {
+	value := e.Unbox(
+		func(r Range) interface{} { return r.ToString() },
+		func(d Duration) interface{} { return d.ToString() },
+		func(o OpenRange) interface{} { return o.ToString() },
+	).(string)
+	return value + " " + e.Summary().ToString()
+}
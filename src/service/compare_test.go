@@ -0,0 +1,79 @@
+package service
+
+import (
+	. "github.com/jotaen/klog/src"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCompareIdenticalRecords(t *testing.T) {
+	r := NewRecord(Ɀ_Date_(2020, 1, 1))
+	r.AddDuration(NewDuration(2, 0), "Work")
+	assert.Len(t, CompareRecords([]Record{r}, []Record{r}), 0)
+}
+
+func TestCompareAddedAndRemovedRecords(t *testing.T) {
+	r1 := NewRecord(Ɀ_Date_(2020, 1, 2))
+	r1.AddDuration(NewDuration(2, 0), "")
+	r2 := NewRecord(Ɀ_Date_(2020, 1, 1))
+	r2.AddDuration(NewDuration(1, 0), "")
+
+	changes := CompareRecords([]Record{r1}, []Record{r2})
+	require.Len(t, changes, 2)
+
+	assert.Equal(t, Ɀ_Date_(2020, 1, 1), changes[0].Date)
+	assert.Equal(t, RECORD_ADDED, changes[0].Type)
+	assert.Equal(t, NewDuration(1, 0), changes[0].TotalDelta)
+	assert.Len(t, changes[0].AddedEntries, 1)
+	assert.Len(t, changes[0].RemovedEntries, 0)
+
+	assert.Equal(t, Ɀ_Date_(2020, 1, 2), changes[1].Date)
+	assert.Equal(t, RECORD_REMOVED, changes[1].Type)
+	assert.Equal(t, NewDuration(-2, 0), changes[1].TotalDelta)
+	assert.Len(t, changes[1].AddedEntries, 0)
+	assert.Len(t, changes[1].RemovedEntries, 1)
+}
+
+func TestCompareChangedEntries(t *testing.T) {
+	old := NewRecord(Ɀ_Date_(2020, 1, 1))
+	old.AddDuration(NewDuration(1, 0), "Same")
+	old.AddDuration(NewDuration(1, 0), "Same")
+	old.AddRange(Ɀ_Range_(Ɀ_Time_(8, 0), Ɀ_Time_(9, 0)), "Meeting")
+	new := NewRecord(Ɀ_Date_(2020, 1, 1))
+	new.AddDuration(NewDuration(1, 0), "Same")
+	new.AddRange(Ɀ_Range_(Ɀ_Time_(8, 0), Ɀ_Time_(9, 30)), "Meeting")
+
+	changes := CompareRecords([]Record{old}, []Record{new})
+	require.Len(t, changes, 1)
+	assert.Equal(t, RECORD_CHANGED, changes[0].Type)
+	assert.Equal(t, NewDuration(0, -30), changes[0].TotalDelta)
+	require.Len(t, changes[0].RemovedEntries, 2)
+	assert.Equal(t, NewDuration(1, 0), changes[0].RemovedEntries[0].Duration())
+	assert.Equal(t, NewDuration(1, 0), changes[0].RemovedEntries[1].Duration())
+	require.Len(t, changes[0].AddedEntries, 1)
+	assert.Equal(t, NewDuration(1, 30), changes[0].AddedEntries[0].Duration())
+}
+
+func TestCompareChangedHeadline(t *testing.T) {
+	old := NewRecord(Ɀ_Date_(2020, 1, 1))
+	new := NewRecord(Ɀ_Date_(2020, 1, 1))
+	new.SetShouldTotal(NewDuration(8, 0))
+
+	changes := CompareRecords([]Record{old}, []Record{new})
+	require.Len(t, changes, 1)
+	assert.Equal(t, RECORD_CHANGED, changes[0].Type)
+	assert.Equal(t, NewDuration(0, 0), changes[0].TotalDelta)
+}
+
+func TestCompareTreatsRecordsOfSameDateAsOne(t *testing.T) {
+	old := NewRecord(Ɀ_Date_(2020, 1, 1))
+	old.AddDuration(NewDuration(1, 0), "")
+	old.AddDuration(NewDuration(2, 0), "")
+	new1 := NewRecord(Ɀ_Date_(2020, 1, 1))
+	new1.AddDuration(NewDuration(1, 0), "")
+	new2 := NewRecord(Ɀ_Date_(2020, 1, 1))
+	new2.AddDuration(NewDuration(2, 0), "")
+
+	assert.Len(t, CompareRecords([]Record{old}, []Record{new1, new2}), 0)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	. "github.com/jotaen/klog/src"
+)
+
+// DayType classifies days off, such as vacation. The should-total time of
+// such days is credited, i.e. it counts towards the total time without
+// being worked time. Public holidays don’t have a should-total at all.
+type DayType string
+
+const (
+	RegularDay    DayType = ""
+	Vacation      DayType = "vacation"
+	SickLeave     DayType = "sick"
+	PublicHoliday DayType = "holiday"
+)
+
+var DayTypes = []DayType{Vacation, SickLeave, PublicHoliday}
+
+// DayTypeOf returns the type of the record. A record can be marked by a tag
+// in its summary (e.g. `#vacation`), otherwise the schedule is consulted.
+func DayTypeOf(schedule *Schedule, r Record) DayType {
+	tags := r.Summary().Tags()
+	for _, t := range DayTypes {
+		if tags[NewTag(string(t))] {
+			return t
+		}
+	}
+	return schedule.DayTypeAt(r.Date())
+}
+
+// CreditSum calculates the credited time of records, which is the
+// should-total of all records that are days off (except public holidays).
+func CreditSum(schedule *Schedule, rs ...Record) Duration {
+	credit := NewDuration(0, 0)
+	for _, r := range rs {
+		t := DayTypeOf(schedule, r)
+		if t == RegularDay || t == PublicHoliday {
+			continue
+		}
+		credit = credit.Plus(ShouldTotalSum(schedule, r))
+	}
+	return credit
+}
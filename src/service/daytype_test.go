@@ -0,0 +1,35 @@
+package service
+
+import (
+	. "github.com/jotaen/klog/src"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDayTypeOfRecordFromSummaryTag(t *testing.T) {
+	r1 := NewRecord(Ɀ_Date_(2020, 1, 1))
+	_ = r1.SetSummary("Off to the mountains #vacation")
+	r2 := NewRecord(Ɀ_Date_(2020, 1, 2))
+	_ = r2.SetSummary("#Sick")
+	r3 := NewRecord(Ɀ_Date_(2020, 1, 3))
+	assert.Equal(t, Vacation, DayTypeOf(nil, r1))
+	assert.Equal(t, SickLeave, DayTypeOf(nil, r2))
+	assert.Equal(t, RegularDay, DayTypeOf(nil, r3))
+	assert.Equal(t, PublicHoliday, DayTypeOf(&Schedule{Holidays: []Holiday{{Ɀ_Date_(2020, 1, 3), ""}}}, r3))
+}
+
+func TestCreditSumCountsShouldTotalOfDaysOffExceptHolidays(t *testing.T) {
+	s := &Schedule{Periods: []SchedulePeriod{{
+		Since: Ɀ_Date_(2020, 1, 1),
+		Hours: map[int]Duration{3: NewDuration(8, 0), 4: NewDuration(6, 0)},
+	}}}
+	r1 := NewRecord(Ɀ_Date_(2020, 1, 1)) // Wednesday
+	_ = r1.SetSummary("#holiday")
+	r2 := NewRecord(Ɀ_Date_(2020, 1, 2)) // Thursday
+	r2.SetShouldTotal(NewDuration(4, 0))
+	_ = r2.SetSummary("#vacation")
+	r3 := NewRecord(Ɀ_Date_(2020, 1, 8))
+	r3.AddDuration(NewDuration(8, 0), "")
+	assert.Equal(t, NewDuration(4, 0), CreditSum(s, r1, r2, r3))
+	assert.Equal(t, NewShouldTotal(4, 0), ShouldTotalSum(s, r1, r2))
+}
@@ -56,10 +56,29 @@ func HypotheticalTotal(until time.Time, rs ...Record) (Duration, bool) {
 }
 
 // ShouldTotalSum calculates the overall should-total time of records.
-func ShouldTotalSum(rs ...Record) ShouldTotal {
+// For records without should-total, the schedule is consulted (if given),
+// unless the record is a public holiday.
+func ShouldTotalSum(schedule *Schedule, rs ...Record) ShouldTotal {
 	total := NewDuration(0, 0)
 	for _, r := range rs {
-		total = total.Plus(r.ShouldTotal())
+		if r.HasShouldTotal() {
+			total = total.Plus(r.ShouldTotal())
+			continue
+		}
+		if DayTypeOf(schedule, r) == PublicHoliday {
+			continue
+		}
+		total = total.Plus(schedule.ShouldTotalAt(r.Date()))
+	}
+	return NewShouldTotal(0, total.InMinutes())
+}
+
+// UnrecordedShouldTotal calculates the should-total time of all days within
+// the given period that don’t have a record, according to the schedule.
+func UnrecordedShouldTotal(schedule *Schedule, from Date, until Date, rs ...Record) ShouldTotal {
+	total := NewDuration(0, 0)
+	for _, d := range schedule.UnrecordedDays(from, until, rs...) {
+		total = total.Plus(schedule.ShouldTotalAt(d))
 	}
 	return NewShouldTotal(0, total.InMinutes())
 }
@@ -45,3 +45,24 @@ func TestSumUpHypotheticalTotalAtGivenTime(t *testing.T) {
 	assert.True(t, isOngoing3)
 	assert.Equal(t, NewDuration(2+(1+4)+18+3, 14+53+1), ht3)
 }
+
+func TestShouldTotalSumFallsBackToSchedule(t *testing.T) {
+	s := &Schedule{Periods: []SchedulePeriod{{
+		Since: Ɀ_Date_(2020, 1, 1),
+		Hours: map[int]Duration{3: NewDuration(8, 0), 4: NewDuration(6, 0)},
+	}}}
+	r1 := NewRecord(Ɀ_Date_(2020, 1, 1)) // Wednesday
+	r2 := NewRecord(Ɀ_Date_(2020, 1, 2)) // Thursday
+	r2.SetShouldTotal(NewDuration(1, 0))
+	assert.Equal(t, NewShouldTotal(9, 0), ShouldTotalSum(s, r1, r2))
+	assert.Equal(t, NewShouldTotal(1, 0), ShouldTotalSum(nil, r1, r2))
+}
+
+func TestUnrecordedShouldTotalCountsDaysWithoutRecord(t *testing.T) {
+	s := &Schedule{Periods: []SchedulePeriod{{
+		Since: Ɀ_Date_(2020, 1, 1),
+		Hours: map[int]Duration{3: NewDuration(8, 0), 4: NewDuration(6, 0)},
+	}}}
+	r := NewRecord(Ɀ_Date_(2020, 1, 1))
+	assert.Equal(t, NewShouldTotal(20, 0), UnrecordedShouldTotal(s, Ɀ_Date_(2020, 1, 1), Ɀ_Date_(2020, 1, 9), r))
+}
@@ -3,6 +3,7 @@ package service
 import (
 	. "github.com/jotaen/klog/src"
 	gosort "sort"
+	"strings"
 )
 
 type FilterQry struct {
@@ -10,6 +11,7 @@ type FilterQry struct {
 	BeforeOrEqual Date
 	AfterOrEqual  Date
 	Dates         []Date
+	Properties    []Property
 }
 
 // Filter returns all records the matches the query.
@@ -18,13 +20,7 @@ func Filter(rs []Record, o FilterQry) []Record {
 	dates := newDateSet(o.Dates)
 	var records []Record
 	for _, r := range rs {
-		if len(dates) > 0 && !dates[NewDayHash(r.Date())] {
-			continue
-		}
-		if o.BeforeOrEqual != nil && !o.BeforeOrEqual.IsAfterOrEqual(r.Date()) {
-			continue
-		}
-		if o.AfterOrEqual != nil && !r.Date().IsAfterOrEqual(o.AfterOrEqual) {
+		if !isMatchingDate(dates, o, r) || !isMatchingProperties(o.Properties, r) {
 			continue
 		}
 		if len(o.Tags) > 0 {
@@ -39,6 +35,19 @@ func Filter(rs []Record, o FilterQry) []Record {
 	return records
 }
 
+// Match checks whether a record satisfies the query. As opposed to Filter,
+// the record is not reduced to the matching entries, but it’s left untouched.
+func Match(r Record, o FilterQry) bool {
+	if !isMatchingDate(newDateSet(o.Dates), o, r) || !isMatchingProperties(o.Properties, r) {
+		return false
+	}
+	if len(o.Tags) > 0 {
+		isRecordMatch, matchingEntries := findMatchingEntries(o.Tags, r)
+		return isRecordMatch || len(matchingEntries) > 0
+	}
+	return true
+}
+
 // Sort orders the records by date.
 func Sort(rs []Record, startWithOldest bool) []Record {
 	sorted := append([]Record(nil), rs...)
@@ -52,10 +61,50 @@ func Sort(rs []Record, startWithOldest bool) []Record {
 	return sorted
 }
 
+func isMatchingDate(dates map[DayHash]bool, o FilterQry, r Record) bool {
+	if len(dates) > 0 && !dates[NewDayHash(r.Date())] {
+		return false
+	}
+	if o.BeforeOrEqual != nil && !o.BeforeOrEqual.IsAfterOrEqual(r.Date()) {
+		return false
+	}
+	if o.AfterOrEqual != nil && !r.Date().IsAfterOrEqual(o.AfterOrEqual) {
+		return false
+	}
+	return true
+}
+
+// isMatchingProperties checks whether the record has all queried properties.
+// A queried property without value matches regardless of the record’s value.
+func isMatchingProperties(queriedProperties []Property, r Record) bool {
+	for _, q := range queriedProperties {
+		p, ok := r.Property(q.Key)
+		if !ok {
+			return false
+		}
+		if !q.IsFlag() && !strings.EqualFold(p.Value, q.Value) {
+			return false
+		}
+	}
+	return true
+}
+
 func reduceRecordToMatchingTags(queriedTags []string, r Record) (Record, bool) {
-	if isSubsetOf(queriedTags, r.Summary().Tags()) {
+	isRecordMatch, matchingEntries := findMatchingEntries(queriedTags, r)
+	if isRecordMatch {
 		return r, true
 	}
+	if len(matchingEntries) == 0 {
+		return nil, false
+	}
+	r.SetEntries(matchingEntries)
+	return r, true
+}
+
+func findMatchingEntries(queriedTags []string, r Record) (bool, []Entry) {
+	if isSubsetOf(queriedTags, r.Summary().Tags()) {
+		return true, nil
+	}
 	_, tagsByEntry := EntryTagLookup(r)
 	var matchingEntries []Entry
 	for _, e := range r.Entries() {
@@ -63,11 +112,7 @@ func reduceRecordToMatchingTags(queriedTags []string, r Record) (Record, bool) {
 			matchingEntries = append(matchingEntries, e)
 		}
 	}
-	if len(matchingEntries) == 0 {
-		return nil, false
-	}
-	r.SetEntries(matchingEntries)
-	return r, true
+	return false, matchingEntries
 }
 
 func isSubsetOf(queriedTags []string, allTags TagSet) bool {
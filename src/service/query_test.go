@@ -15,6 +15,8 @@ func sampleRecordsForQuerying() []Record {
 			return r
 		}(), func() Record {
 			r := NewRecord(Ɀ_Date_(1999, 12, 31))
+			r.SetProperty(Property{Key: "location", Value: "office"})
+			r.SetProperty(Property{Key: "billable"})
 			r.AddDuration(NewDuration(5, 0), "#bar")
 			return r
 		}(), func() Record {
@@ -26,6 +28,7 @@ func sampleRecordsForQuerying() []Record {
 			return r
 		}(), func() Record {
 			r := NewRecord(Ɀ_Date_(2000, 1, 2))
+			r.SetProperty(Property{Key: "location", Value: "home"})
 			_ = r.SetSummary("#foo")
 			r.AddDuration(NewDuration(7, 0), "")
 			return r
@@ -61,6 +64,20 @@ func TestQueryWithBefore(t *testing.T) {
 	assert.Equal(t, 1, rs[2].Date().Day())
 }
 
+func TestQueryWithProperties(t *testing.T) {
+	rs := Filter(sampleRecordsForQuerying(), FilterQry{Properties: []Property{{Key: "location"}}})
+	require.Len(t, rs, 2)
+	assert.Equal(t, 31, rs[0].Date().Day())
+	assert.Equal(t, 2, rs[1].Date().Day())
+
+	rs = Filter(sampleRecordsForQuerying(), FilterQry{Properties: []Property{{Key: "location", Value: "Home"}}})
+	require.Len(t, rs, 1)
+	assert.Equal(t, 2, rs[0].Date().Day())
+
+	rs = Filter(sampleRecordsForQuerying(), FilterQry{Properties: []Property{{Key: "location", Value: "home"}, {Key: "billable"}}})
+	require.Len(t, rs, 0)
+}
+
 func TestQueryWithTagOnEntries(t *testing.T) {
 	rs := Filter(sampleRecordsForQuerying(), FilterQry{Tags: []string{"bar"}})
 	require.Len(t, rs, 3)
@@ -111,3 +128,13 @@ func TestQueryWithSorting(t *testing.T) {
 		assert.Equal(t, []Record{ss[4], ss[3], ss[2], ss[1], ss[0]}, descending)
 	}
 }
+
+func TestMatchDoesNotAlterRecord(t *testing.T) {
+	rs := sampleRecordsForQuerying()
+	qry := FilterQry{Tags: []string{"bar"}, AfterOrEqual: Ɀ_Date_(2000, 1, 1)}
+	assert.False(t, Match(rs[1], qry))
+	assert.True(t, Match(rs[2], qry))
+	assert.Len(t, rs[2].Entries(), 3)
+	assert.False(t, Match(rs[3], qry))
+	assert.True(t, Match(rs[4], qry))
+}
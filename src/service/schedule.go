@@ -0,0 +1,138 @@
+package service
+
+import (
+	. "github.com/jotaen/klog/src"
+)
+
+// Schedule describes the regular working hours. It determines the should-total
+// time of days that don’t specify it explicitly.
+type Schedule struct {
+	// Periods are the successive work arrangements (e.g. full-time, part-time).
+	// Every period takes effect at its `Since` date and lasts until the next
+	// period begins.
+	Periods []SchedulePeriod
+
+	// Holidays are public holidays, which have no should-total time.
+	Holidays []Holiday
+
+	// Vacation and Leave are days off. Their should-total time is credited,
+	// so no time is owed on them.
+	Vacation []Date
+	Leave    []Leave
+
+	// Quotas are the number of days per year that are available for a day type.
+	Quotas map[DayType]int
+}
+
+type SchedulePeriod struct {
+	Since Date
+
+	// Hours are the should-totals per weekday, where 1 is Monday and 7 is Sunday.
+	Hours map[int]Duration
+}
+
+type Holiday struct {
+	Date Date
+	Name string
+}
+
+// Leave is a day type that applies to a range of dates (inclusive).
+type Leave struct {
+	Type  DayType
+	From  Date
+	Until Date
+}
+
+// ShouldTotalAt returns the should-total time of the given day, according
+// to the working hours. Public holidays have no should-total, whereas the
+// should-total of other days off is credited (see `CreditSum`).
+func (s *Schedule) ShouldTotalAt(d Date) ShouldTotal {
+	if _, isHoliday := s.HolidayAt(d); isHoliday {
+		return NewShouldTotal(0, 0)
+	}
+	return s.WorkingHoursAt(d)
+}
+
+// WorkingHoursAt returns the regular working hours at the given day,
+// regardless of whether it is a day off.
+func (s *Schedule) WorkingHoursAt(d Date) ShouldTotal {
+	none := NewShouldTotal(0, 0)
+	if s == nil {
+		return none
+	}
+	var period *SchedulePeriod
+	for i, p := range s.Periods {
+		if d.IsAfterOrEqual(p.Since) && (period == nil || p.Since.IsAfterOrEqual(period.Since)) {
+			period = &s.Periods[i]
+		}
+	}
+	if period == nil || period.Hours[d.Weekday()] == nil {
+		return none
+	}
+	return NewShouldTotal(0, period.Hours[d.Weekday()].InMinutes())
+}
+
+// DayTypeAt returns the type of the given day, as defined by the schedule.
+func (s *Schedule) DayTypeAt(d Date) DayType {
+	if s == nil {
+		return RegularDay
+	}
+	if _, isHoliday := s.HolidayAt(d); isHoliday {
+		return PublicHoliday
+	}
+	for _, v := range s.Vacation {
+		if v.IsEqualTo(d) {
+			return Vacation
+		}
+	}
+	for _, l := range s.Leave {
+		if d.IsAfterOrEqual(l.From) && l.Until.IsAfterOrEqual(d) {
+			return l.Type
+		}
+	}
+	return RegularDay
+}
+
+// HolidayAt returns the public holiday at the given day, if there is one.
+func (s *Schedule) HolidayAt(d Date) (Holiday, bool) {
+	if s == nil {
+		return Holiday{}, false
+	}
+	for _, h := range s.Holidays {
+		if h.Date.IsEqualTo(d) {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// QuotaOf returns the number of days per year that are available for the
+// day type, if there is a quota.
+func (s *Schedule) QuotaOf(t DayType) (int, bool) {
+	if s == nil || s.Quotas == nil {
+		return 0, false
+	}
+	quota, ok := s.Quotas[t]
+	return quota, ok
+}
+
+// UnrecordedDays returns all regular days within the given period (inclusive)
+// that don’t have a record, but that have a should-total according to the
+// schedule.
+func (s *Schedule) UnrecordedDays(from Date, until Date, rs ...Record) []Date {
+	if s == nil {
+		return nil
+	}
+	recorded := make(map[DayHash]bool, len(rs))
+	for _, r := range rs {
+		recorded[NewDayHash(r.Date())] = true
+	}
+	var result []Date
+	for d := from; until.IsAfterOrEqual(d); d = d.PlusDays(1) {
+		if recorded[NewDayHash(d)] || s.DayTypeAt(d) != RegularDay || s.ShouldTotalAt(d).InMinutes() == 0 {
+			continue
+		}
+		result = append(result, d)
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	. "github.com/jotaen/klog/src"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func fullTimeThenPartTime() *Schedule {
+	return &Schedule{
+		Periods: []SchedulePeriod{{
+			Since: Ɀ_Date_(2020, 1, 1),
+			Hours: map[int]Duration{1: NewDuration(8, 0), 2: NewDuration(8, 0), 3: NewDuration(8, 0), 4: NewDuration(8, 0), 5: NewDuration(8, 0)},
+		}, {
+			Since: Ɀ_Date_(2021, 1, 1),
+			Hours: map[int]Duration{1: NewDuration(6, 0), 2: NewDuration(6, 0), 3: NewDuration(6, 0)},
+		}},
+		Holidays: []Holiday{{Ɀ_Date_(2020, 12, 24), "Christmas Eve"}},
+		Vacation: []Date{Ɀ_Date_(2021, 1, 4)},
+	}
+}
+
+func TestScheduleDeterminesShouldTotalPerDay(t *testing.T) {
+	s := fullTimeThenPartTime()
+	for _, x := range []struct {
+		date     Date
+		expected ShouldTotal
+	}{
+		{Ɀ_Date_(2019, 12, 31), NewShouldTotal(0, 0)},
+		{Ɀ_Date_(2020, 12, 23), NewShouldTotal(8, 0)},
+		{Ɀ_Date_(2020, 12, 24), NewShouldTotal(0, 0)},
+		{Ɀ_Date_(2020, 12, 26), NewShouldTotal(0, 0)},
+		{Ɀ_Date_(2021, 1, 1), NewShouldTotal(0, 0)},
+		{Ɀ_Date_(2021, 1, 4), NewShouldTotal(6, 0)},
+		{Ɀ_Date_(2021, 1, 5), NewShouldTotal(6, 0)},
+	} {
+		assert.Equal(t, x.expected, s.ShouldTotalAt(x.date), x.date.ToString())
+	}
+}
+
+func TestScheduleDeterminesHolidays(t *testing.T) {
+	s := fullTimeThenPartTime()
+	h, isHoliday := s.HolidayAt(Ɀ_Date_(2020, 12, 24))
+	assert.True(t, isHoliday)
+	assert.Equal(t, "Christmas Eve", h.Name)
+	assert.Equal(t, NewShouldTotal(8, 0), s.WorkingHoursAt(Ɀ_Date_(2020, 12, 24)))
+	_, isHoliday = s.HolidayAt(Ɀ_Date_(2020, 12, 23))
+	assert.False(t, isHoliday)
+}
+
+func TestScheduleDeterminesDayTypes(t *testing.T) {
+	s := fullTimeThenPartTime()
+	s.Leave = []Leave{{SickLeave, Ɀ_Date_(2021, 2, 1), Ɀ_Date_(2021, 2, 3)}}
+	assert.Equal(t, PublicHoliday, s.DayTypeAt(Ɀ_Date_(2020, 12, 24)))
+	assert.Equal(t, Vacation, s.DayTypeAt(Ɀ_Date_(2021, 1, 4)))
+	assert.Equal(t, SickLeave, s.DayTypeAt(Ɀ_Date_(2021, 2, 3)))
+	assert.Equal(t, RegularDay, s.DayTypeAt(Ɀ_Date_(2021, 2, 4)))
+}
+
+func TestNoScheduleMeansNoShouldTotal(t *testing.T) {
+	var s *Schedule
+	assert.Equal(t, NewShouldTotal(0, 0), s.ShouldTotalAt(Ɀ_Date_(2020, 1, 1)))
+	assert.Nil(t, s.UnrecordedDays(Ɀ_Date_(2020, 1, 1), Ɀ_Date_(2020, 1, 31)))
+}
+
+func TestFindsUnrecordedDaysThatHaveShouldTotal(t *testing.T) {
+	s := fullTimeThenPartTime()
+	r := NewRecord(Ɀ_Date_(2020, 12, 22))
+	days := s.UnrecordedDays(Ɀ_Date_(2020, 12, 21), Ɀ_Date_(2020, 12, 28), r)
+	assert.Equal(t, []Date{
+		Ɀ_Date_(2020, 12, 21),
+		Ɀ_Date_(2020, 12, 23),
+		Ɀ_Date_(2020, 12, 25),
+		Ɀ_Date_(2020, 12, 28),
+	}, days)
+}
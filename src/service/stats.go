@@ -0,0 +1,216 @@
+package service
+
+import (
+	. "github.com/jotaen/klog/src"
+	"sort"
+)
+
+// Stats are statistical figures about the records.
+type Stats struct {
+	// NumberOfDays is the number of distinct days that have records.
+	NumberOfDays int
+
+	// Weekdays contains the figures per weekday (1 = Monday, 7 = Sunday).
+	Weekdays map[int]WeekdayStats
+
+	// StartTimes and EndTimes are the distributions of the first range start
+	// and of the last range end per day, by hour of the day.
+	StartTimes map[int]int
+	EndTimes   map[int]int
+
+	// MedianStart and MedianEnd are nil if there are no ranges.
+	MedianStart Time
+	MedianEnd   Time
+
+	// LongestStreak is the longest period of consecutive days with time.
+	// LongestWeekdayStreak is the same, except that weekends are disregarded.
+	LongestStreak        Streak
+	LongestWeekdayStreak Streak
+
+	// EntriesPerTag is the number of entries that match a tag.
+	EntriesPerTag map[Tag]int
+}
+
+type WeekdayStats struct {
+	NumberOfDays int
+	Mean         Duration
+	Median       Duration
+}
+
+// Streak is a period of consecutive days. It is empty if `Days` is 0.
+type Streak struct {
+	From  Date
+	Until Date
+	Days  int
+}
+
+// CalculateStats computes the statistical figures of the records. Records
+// at the same date are evaluated as one day.
+func CalculateStats(rs ...Record) Stats {
+	stats := Stats{
+		Weekdays:      make(map[int]WeekdayStats),
+		StartTimes:    make(map[int]int),
+		EndTimes:      make(map[int]int),
+		EntriesPerTag: make(map[Tag]int),
+	}
+	days := make(map[DayHash][]Record)
+	var dates []Date
+	for _, r := range Sort(rs, true) {
+		hash := NewDayHash(r.Date())
+		if days[hash] == nil {
+			dates = append(dates, r.Date())
+		}
+		days[hash] = append(days[hash], r)
+	}
+	stats.NumberOfDays = len(dates)
+
+	totalsPerWeekday := make(map[int][]Duration)
+	var starts, ends []Time
+	var workedDates []Date
+	for _, d := range dates {
+		day := days[NewDayHash(d)]
+		total := Total(day...)
+		totalsPerWeekday[d.Weekday()] = append(totalsPerWeekday[d.Weekday()], total)
+		if total.InMinutes() > 0 {
+			workedDates = append(workedDates, d)
+		}
+		start, end := firstStartAndLastEnd(day)
+		if start != nil {
+			starts = append(starts, start)
+			stats.StartTimes[start.Hour()]++
+		}
+		if end != nil {
+			ends = append(ends, end)
+			stats.EndTimes[end.Hour()]++
+		}
+	}
+	for weekday, totals := range totalsPerWeekday {
+		stats.Weekdays[weekday] = WeekdayStats{
+			NumberOfDays: len(totals),
+			Mean:         mean(totals),
+			Median:       median(totals),
+		}
+	}
+	stats.MedianStart = medianTime(starts)
+	stats.MedianEnd = medianTime(ends)
+	stats.LongestStreak = longestStreak(workedDates, false)
+	stats.LongestWeekdayStreak = longestStreak(workedDates, true)
+
+	entriesByTag, _ := EntryTagLookup(rs...)
+	for t, es := range entriesByTag {
+		stats.EntriesPerTag[t] = len(es)
+	}
+	return stats
+}
+
+func firstStartAndLastEnd(rs []Record) (Time, Time) {
+	var start, end Time
+	for _, r := range rs {
+		for _, e := range r.Entries() {
+			e.Unbox(
+				func(r Range) interface{} {
+					if start == nil || start.IsAfterOrEqual(r.Start()) {
+						start = r.Start()
+					}
+					if end == nil || r.End().IsAfterOrEqual(end) {
+						end = r.End()
+					}
+					return nil
+				},
+				func(Duration) interface{} { return nil },
+				func(o OpenRange) interface{} {
+					if start == nil || start.IsAfterOrEqual(o.Start()) {
+						start = o.Start()
+					}
+					return nil
+				},
+			)
+		}
+	}
+	return start, end
+}
+
+func mean(ds []Duration) Duration {
+	if len(ds) == 0 {
+		return NewDuration(0, 0)
+	}
+	sum := 0
+	for _, d := range ds {
+		sum += d.InMinutes()
+	}
+	return NewDuration(0, sum/len(ds))
+}
+
+func median(ds []Duration) Duration {
+	if len(ds) == 0 {
+		return NewDuration(0, 0)
+	}
+	mins := make([]int, len(ds))
+	for i, d := range ds {
+		mins[i] = d.InMinutes()
+	}
+	sort.Ints(mins)
+	middle := len(mins) / 2
+	if len(mins)%2 == 0 {
+		return NewDuration(0, (mins[middle-1]+mins[middle])/2)
+	}
+	return NewDuration(0, mins[middle])
+}
+
+func medianTime(ts []Time) Time {
+	if len(ts) == 0 {
+		return nil
+	}
+	offsets := make([]Duration, len(ts))
+	for i, t := range ts {
+		offsets[i] = t.MidnightOffset()
+	}
+	m := median(offsets).InMinutes()
+	dayShift := 0
+	for m < 0 {
+		m += 24 * 60
+		dayShift--
+	}
+	for m >= 24*60 {
+		m -= 24 * 60
+		dayShift++
+	}
+	t, _ := NewTime(m/60, m%60)
+	switch dayShift {
+	case -1:
+		t, _ = NewTimeYesterday(m/60, m%60)
+	case 1:
+		t, _ = NewTimeTomorrow(m/60, m%60)
+	}
+	return t
+}
+
+// longestStreak finds the longest sequence of consecutive dates. The dates
+// must be sorted. If `skipWeekends` is set, weekends don’t break streaks.
+func longestStreak(dates []Date, skipWeekends bool) Streak {
+	longest := Streak{}
+	current := Streak{}
+	for _, d := range dates {
+		if skipWeekends && d.Weekday() > 5 {
+			continue
+		}
+		if current.Days > 0 && nextDay(current.Until, skipWeekends).IsEqualTo(d) {
+			current.Until = d
+			current.Days++
+		} else {
+			current = Streak{From: d, Until: d, Days: 1}
+		}
+		if current.Days > longest.Days {
+			longest = current
+		}
+	}
+	return longest
+}
+
+func nextDay(d Date, skipWeekends bool) Date {
+	next := d.PlusDays(1)
+	for skipWeekends && next.Weekday() > 5 {
+		next = next.PlusDays(1)
+	}
+	return next
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	. "github.com/jotaen/klog/src"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func sampleRecordsForStats() []Record {
+	r1 := NewRecord(Ɀ_Date_(2021, 3, 1))
+	r1.AddRange(Ɀ_Range_(Ɀ_Time_(8, 0), Ɀ_Time_(12, 0)), "#work")
+	r1.AddRange(Ɀ_Range_(Ɀ_Time_(13, 0), Ɀ_Time_(17, 0)), "")
+	r2 := NewRecord(Ɀ_Date_(2021, 3, 2))
+	r2.AddRange(Ɀ_Range_(Ɀ_Time_(9, 30), Ɀ_Time_(15, 30)), "#work")
+	r3 := NewRecord(Ɀ_Date_(2021, 3, 3))
+	r3.AddDuration(NewDuration(2, 0), "")
+	r4 := NewRecord(Ɀ_Date_(2021, 3, 4))
+	r4.AddDuration(NewDuration(1, 0), "")
+	r5 := NewRecord(Ɀ_Date_(2021, 3, 5))
+	r5.AddRange(Ɀ_Range_(Ɀ_Time_(7, 45), Ɀ_Time_(16, 0)), "")
+	r6 := NewRecord(Ɀ_Date_(2021, 3, 7))
+	r7 := NewRecord(Ɀ_Date_(2021, 3, 8))
+	r7.AddRange(Ɀ_Range_(Ɀ_Time_(8, 30), Ɀ_Time_(12, 30)), "")
+	return []Record{r7, r1, r2, r3, r4, r5, r6}
+}
+
+func TestStatsOfEmptyRecords(t *testing.T) {
+	stats := CalculateStats()
+	assert.Equal(t, 0, stats.NumberOfDays)
+	assert.Empty(t, stats.Weekdays)
+	assert.Nil(t, stats.MedianStart)
+	assert.Equal(t, 0, stats.LongestStreak.Days)
+}
+
+func TestStatsPerWeekday(t *testing.T) {
+	stats := CalculateStats(sampleRecordsForStats()...)
+	assert.Equal(t, 7, stats.NumberOfDays)
+	assert.Equal(t, WeekdayStats{2, NewDuration(6, 0), NewDuration(6, 0)}, stats.Weekdays[1])
+	assert.Equal(t, WeekdayStats{1, NewDuration(6, 0), NewDuration(6, 0)}, stats.Weekdays[2])
+	assert.Equal(t, WeekdayStats{1, NewDuration(0, 0), NewDuration(0, 0)}, stats.Weekdays[7])
+	_, hasSaturday := stats.Weekdays[6]
+	assert.False(t, hasSaturday)
+}
+
+func TestStatsOfStartAndEndTimes(t *testing.T) {
+	stats := CalculateStats(sampleRecordsForStats()...)
+	assert.Equal(t, map[int]int{7: 1, 8: 2, 9: 1}, stats.StartTimes)
+	assert.Equal(t, map[int]int{12: 1, 15: 1, 16: 1, 17: 1}, stats.EndTimes)
+	assert.Equal(t, Ɀ_Time_(8, 15), stats.MedianStart)
+	assert.Equal(t, Ɀ_Time_(15, 45), stats.MedianEnd)
+}
+
+func TestStatsOfStreaks(t *testing.T) {
+	stats := CalculateStats(sampleRecordsForStats()...)
+	assert.Equal(t, Streak{Ɀ_Date_(2021, 3, 1), Ɀ_Date_(2021, 3, 5), 5}, stats.LongestStreak)
+	assert.Equal(t, Streak{Ɀ_Date_(2021, 3, 1), Ɀ_Date_(2021, 3, 8), 6}, stats.LongestWeekdayStreak)
+}
+
+func TestStatsOfTags(t *testing.T) {
+	stats := CalculateStats(sampleRecordsForStats()...)
+	assert.Equal(t, map[Tag]int{"work": 2}, stats.EntriesPerTag)
+}